@@ -15,6 +15,7 @@ const (
 
 	VehicleVIN   = "VIN12345678901234"
 	VehicleICCID = "12345678901234567890"
+	StorageCode  = "BP0123456789" // 可充电储能系统编码
 )
 
 func main() {
@@ -57,7 +58,7 @@ func main() {
 	// 2. 车辆登入 (0x01)
 	// ==========================================
 	fmt.Println(">> [2/3] 发送车辆登入请求 (0x01)...")
-	vehPkt := builder.BuildVehicleLogin(VehicleICCID)
+	vehPkt := builder.BuildVehicleLogin(VehicleICCID, StorageCode)
 	if _, err := conn.Write(vehPkt); err != nil {
 		panic(err)
 	}
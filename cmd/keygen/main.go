@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+// 生成本地联调用的 SM2/SM4 密钥
+// 网关侧密钥写入 <dir>/<vin|platform>/<id>/ (与 gbt32960.crypto.key_dir 目录约定一致)，
+// 终端侧密钥写入 <dir>/terminal/<id>/，两侧的 SM2 公钥互为对端公钥
+func main() {
+	dir := flag.String("dir", "keys", "密钥根目录")
+	vin := flag.String("vin", "", "车辆 VIN")
+	platform := flag.String("platform", "", "平台登入用户名")
+	withIV := flag.Bool("iv", false, "生成 SM4 初始向量 (CBC 模式)")
+	flag.Parse()
+
+	kind, id := "vin", *vin
+	if id == "" {
+		kind, id = "platform", *platform
+	}
+	if id == "" {
+		fmt.Println("请指定 -vin 或 -platform")
+		os.Exit(1)
+	}
+
+	gatewayPriv, gatewayPub, err := gbt32960.GenerateSM2Key()
+	if err != nil {
+		fail(err)
+	}
+	terminalPriv, terminalPub, err := gbt32960.GenerateSM2Key()
+	if err != nil {
+		fail(err)
+	}
+	sm4Key := randomHex(16)
+	var sm4IV string
+	if *withIV {
+		sm4IV = randomHex(16)
+	}
+
+	gatewayDir := filepath.Join(*dir, kind, id)
+	terminalDir := filepath.Join(*dir, "terminal", id)
+	files := map[string][]byte{
+		filepath.Join(gatewayDir, "sm2_private.pem"):      gatewayPriv,
+		filepath.Join(gatewayDir, "sm2_peer_public.pem"):  terminalPub,
+		filepath.Join(gatewayDir, "sm4.key"):              []byte(sm4Key),
+		filepath.Join(terminalDir, "sm2_private.pem"):     terminalPriv,
+		filepath.Join(terminalDir, "sm2_peer_public.pem"): gatewayPub,
+		filepath.Join(terminalDir, "sm4.key"):             []byte(sm4Key),
+	}
+	if sm4IV != "" {
+		files[filepath.Join(gatewayDir, "sm4.iv")] = []byte(sm4IV)
+		files[filepath.Join(terminalDir, "sm4.iv")] = []byte(sm4IV)
+	}
+
+	for path, data := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			fail(err)
+		}
+		fmt.Printf("已写入 %s\n", path)
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		fail(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+func fail(err error) {
+	fmt.Printf("生成密钥失败: %v\n", err)
+	os.Exit(1)
+}
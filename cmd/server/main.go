@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -15,6 +16,7 @@ import (
 	"vehicle-gateway/internal/infra/kafka"
 	"vehicle-gateway/internal/infra/mq"
 	"vehicle-gateway/internal/infra/rabbitmq"
+	protocol "vehicle-gateway/internal/protocol/gbt32960"
 	"vehicle-gateway/internal/server"
 	"vehicle-gateway/internal/usecase"
 	gbt32960 "vehicle-gateway/internal/usecase/gbt32960"
@@ -82,6 +84,15 @@ func main() {
 	}
 	defer producer.Close()
 
+	if tz := cfg.GBT32960.CollectTime.TimeZone; tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			logger.Error("Failed to load collect time zone", zap.String("time_zone", tz), zap.Error(err))
+			panic(err)
+		}
+		protocol.SetTimeZone(loc)
+	}
+
 	// 3. 业务逻辑层 (分发器 & 处理器 & 会话管理)
 	dispatcher := usecase.NewDataDispatcher(producer, 100, logger)
 	dispatcher.Start()
@@ -89,7 +100,24 @@ func main() {
 
 	sm := gbt32960.NewSessionManager(logger)
 	auth := gbt32960.NewInMemoryAuthService(cfg.Auth)
-	h := gbt32960.NewHandler(sm, dispatcher, auth, logger) // Enable Dispatcher (RabbitMQ)
+	keys, err := gbt32960.NewKeyStore(cfg.GBT32960.Crypto)
+	if err != nil {
+		logger.Error("Failed to load crypto keys", zap.Error(err))
+		panic(err)
+	}
+	custom, err := protocol.LoadCustomSchemas(cfg.GBT32960.CustomTypes.SchemaFiles...)
+	if err != nil {
+		logger.Error("Failed to load custom type schemas", zap.Error(err))
+		panic(err)
+	}
+	cells := gbt32960.NewCellAssembler(cfg.GBT32960.CellAssembly, dispatcher, logger)
+	cells.Start()
+	defer cells.Stop()
+	h, err := gbt32960.NewHandler(sm, dispatcher, auth, keys, custom, cells, cfg.GBT32960, mqCfg, logger) // Enable Dispatcher (RabbitMQ)
+	if err != nil {
+		logger.Error("Invalid gbt32960 configuration", zap.Error(err))
+		panic(err)
+	}
 
 	// 4. 服务层
 	srv := server.NewTCPServer(cfg, logger, h)
@@ -9,9 +9,16 @@ import (
 
 // PacketBuilder 帮助构建测试用的 GB/T 32960 报文
 type PacketBuilder struct {
-	VIN string
+	VIN     string
+	Version gbt32960.ProtocolVersion // 报文协议版本, 默认 2016 ("##")
 }
 
+// 2025 版车辆登入扩展字段中上报的终端版本
+const (
+	TerminalHardwareVersion = "H1.00"
+	TerminalFirmwareVersion = "F1.00"
+)
+
 func NewPacketBuilder(vin string) *PacketBuilder {
 	return &PacketBuilder{VIN: vin}
 }
@@ -42,6 +49,7 @@ func (pb *PacketBuilder) BuildPlatformLogin(username, password string) []byte {
 	payload[40] = 0x01
 
 	pkt := &gbt32960.Packet{
+		Version:    pb.Version,
 		Command:    gbt32960.CmdPlatformLogin,
 		VIN:        pb.VIN,
 		Encryption: 0x01,
@@ -51,18 +59,21 @@ func (pb *PacketBuilder) BuildPlatformLogin(username, password string) []byte {
 }
 
 // BuildVehicleLogin 生成车辆登入报文 (0x01)
-func (pb *PacketBuilder) BuildVehicleLogin(iccid string) []byte {
-	// 采集时间 6 + 登入流水号 2 + ICCID 20 + 子系统数 1 + 编码 1 = 30 字节
-	data := make([]byte, 30)
+// codes 为各可充电储能子系统编码, 编码长度 m 取最长的编码, 不足部分补 0
+// 2025 版在编码之后追加登入扩展字段 (终端硬件/固件版本)
+func (pb *PacketBuilder) BuildVehicleLogin(iccid string, codes ...string) []byte {
+	m := 0
+	for _, c := range codes {
+		if len(c) > m {
+			m = len(c)
+		}
+	}
+
+	// 采集时间 6 + 登入流水号 2 + ICCID 20 + 子系统数 1 + 编码长度 1 + 编码 n*m
+	data := make([]byte, 30+len(codes)*m)
 
 	// 当前时间
-	now := time.Now()
-	data[0] = byte(now.Year() - 2000)
-	data[1] = byte(now.Month())
-	data[2] = byte(now.Day())
-	data[3] = byte(now.Hour())
-	data[4] = byte(now.Minute())
-	data[5] = byte(now.Second())
+	copy(data[0:6], gbt32960.EncodeTime(time.Now()))
 
 	// 流水号 (1)
 	binary.BigEndian.PutUint16(data[6:8], 1)
@@ -70,12 +81,19 @@ func (pb *PacketBuilder) BuildVehicleLogin(iccid string) []byte {
 	// ICCID
 	copy(data[8:28], iccid)
 
-	// 子系统个数 (1)
-	data[28] = 1
-	// 编码方式 (1)
-	data[29] = 1
+	// 子系统个数 n 与编码长度 m
+	data[28] = byte(len(codes))
+	data[29] = byte(m)
+	for i, c := range codes {
+		copy(data[30+i*m:30+(i+1)*m], c)
+	}
+	if pb.Version == gbt32960.Version2025 {
+		ext, _ := (&gbt32960.LoginExtension2025{HardwareVersion: TerminalHardwareVersion, FirmwareVersion: TerminalFirmwareVersion}).Marshal()
+		data = append(data, ext...)
+	}
 
 	pkt := &gbt32960.Packet{
+		Version:    pb.Version,
 		Command:    gbt32960.CmdVehicleLogin,
 		VIN:        pb.VIN,
 		Encryption: 0x01,
@@ -84,42 +102,81 @@ func (pb *PacketBuilder) BuildVehicleLogin(iccid string) []byte {
 	return gbt32960.EncodePacket(pkt)
 }
 
-// BuildRealTime 生成实时数据报文 (含整车、电机)
+// BuildRealTime 生成实时数据报文 (整车、驱动电机、位置、极值、报警、储能电压与温度, 信息类型按 Version 选择)
 func (pb *PacketBuilder) BuildRealTime(speed float32, soc byte) []byte {
-	// 1. 整车数据 (20 bytes)
-	vd := make([]byte, 20)
-	vd[0] = 0x01 // Status
-	// 车速 (0.1 km/h)
-	speedRaw := uint16(speed * 10)
-	binary.BigEndian.PutUint16(vd[3:5], speedRaw)
-	// 电流 0A (值 10000)
-	binary.BigEndian.PutUint16(vd[11:13], 10000)
-	vd[13] = soc
-
-	// 2. 驱动电机数据 (1 + 12 = 13 bytes)
-	md := make([]byte, 13)
-	md[0] = 1    // 个数
-	md[1] = 1    // 序号
-	md[2] = 0x01 // 状态
-
-	// 组装完整报文
-	// 时间 (6 bytes)
-	timeBytes := make([]byte, 6)
-	now := time.Now()
-	timeBytes[0] = byte(now.Year() - 2000) // ... simplified filling
-
-	var fullData []byte
-	fullData = append(fullData, timeBytes...)
-	fullData = append(fullData, 0x01) // 类型: 整车
-	fullData = append(fullData, vd...)
-	fullData = append(fullData, 0x02) // 类型: 电机
-	fullData = append(fullData, md...)
+	vehicle := &gbt32960.VehicleData{
+		Status:        0x01,
+		ChargeStatus:  0x03,
+		RunMode:       0x01,
+		Speed:         speed,
+		TotalMileage:  12345.6,
+		Voltage:       380,
+		Current:       -25.5,
+		SOC:           soc,
+		DCStatus:      0x01,
+		Gear:          0x0E,
+		InsulationRes: 5000,
+		AccelPedal:    20,
+	}
+	motor := &gbt32960.MotorData{MotorList: []gbt32960.MotorUnit{{
+		Seq:      1,
+		Status:   0x01,
+		CtrlTemp: 45,
+		Speed:    3000,
+		Torque:   120.5,
+		Temp:     60,
+		Voltage:  380,
+		Current:  50,
+	}}}
+	location := &gbt32960.LocationData{Valid: true, Longitude: 116.397128, Latitude: 39.916527}
+	alarm := gbt32960.NewAlarmData(pb.Version)
+	temps := []int16{25, 26, 25, 27}
+
+	units := []gbt32960.RealTimeUnit{
+		{InfoType: byte(gbt32960.DataTypeVehicle), Body: vehicle.Marshal()},
+		{InfoType: byte(gbt32960.DataTypeMotor), Body: motor.Marshal()},
+		{InfoType: byte(gbt32960.DataTypeLocation), Body: location.Marshal()},
+	}
+	if pb.Version == gbt32960.Version2025 {
+		voltage := &gbt32960.BatteryVoltageData{PackVoltages: []gbt32960.BatteryPackInfo{{
+			PackSeq: 1, Voltage: 380, Current: -25.5, FrameCellVoltages: []float32{3.65, 3.66, 3.65, 3.64},
+		}}}
+		temp := &gbt32960.BatteryTempData{PackTemps: []gbt32960.BatteryPackTemp{{PackSeq: 1, ProbeTemps: temps}}}
+		units = append(units,
+			gbt32960.RealTimeUnit{InfoType: 0x06, Body: alarm.Marshal()},
+			gbt32960.RealTimeUnit{InfoType: 0x07, Body: voltage.Marshal()},
+			gbt32960.RealTimeUnit{InfoType: 0x08, Body: temp.Marshal()},
+		)
+	} else {
+		extreme := &gbt32960.ExtremeData{
+			MaxVoltageSubSysID: 1, MaxVoltageProbeID: 2, MaxVoltage: 3.66,
+			MinVoltageSubSysID: 1, MinVoltageProbeID: 4, MinVoltage: 3.64,
+			MaxTempSubSysID: 1, MaxTempProbeID: 4, MaxTemp: 27,
+			MinTempSubSysID: 1, MinTempProbeID: 1, MinTemp: 25,
+		}
+		voltage := &gbt32960.StorageVoltageData2016{Subsystems: []gbt32960.StorageSubsystemInfo{{
+			SystemNo: 1, Voltage: 380, Current: -25.5, SingleCellCount: 4, StartFrameSeq: 1,
+			CellVoltages: []float32{3.65, 3.66, 3.65, 3.64},
+		}}}
+		temp := &gbt32960.StorageTempData2016{Subsystems: []gbt32960.StorageTempSubsystem{{SystemNo: 1, Temperatures: temps}}}
+		units = append(units,
+			gbt32960.RealTimeUnit{InfoType: byte(gbt32960.DataTypeExtreme), Body: extreme.Marshal()},
+			gbt32960.RealTimeUnit{InfoType: byte(gbt32960.DataTypeAlarm), Body: alarm.Marshal()},
+			gbt32960.RealTimeUnit{InfoType: byte(gbt32960.DataTypeStorageVoltage), Body: voltage.Marshal()},
+			gbt32960.RealTimeUnit{InfoType: byte(gbt32960.DataTypeStorageTemp), Body: temp.Marshal()},
+		)
+	}
+	return pb.BuildRealTimeUnits(time.Now(), units...)
+}
 
+// BuildRealTimeUnits 由信息单元生成实时数据报文 (0x02)
+func (pb *PacketBuilder) BuildRealTimeUnits(collectTime time.Time, units ...gbt32960.RealTimeUnit) []byte {
 	pkt := &gbt32960.Packet{
+		Version:    pb.Version,
 		Command:    gbt32960.CmdRealTime,
 		VIN:        pb.VIN,
-		Encryption: 0x05,
-		DataUnit:   fullData,
+		Encryption: 0x01,
+		DataUnit:   gbt32960.EncodeRealTimeData(collectTime, units...),
 	}
 	return gbt32960.EncodePacket(pkt)
 }
@@ -134,9 +191,10 @@ func (pb *PacketBuilder) BuildLogout(seq uint16) []byte {
 	binary.BigEndian.PutUint16(data[6:8], seq)
 
 	pkt := &gbt32960.Packet{
+		Version:    pb.Version,
 		Command:    gbt32960.CmdLogout,
 		VIN:        pb.VIN,
-		Encryption: 0x05,
+		Encryption: 0x01,
 		DataUnit:   data,
 	}
 	return gbt32960.EncodePacket(pkt)
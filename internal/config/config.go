@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -9,6 +11,102 @@ type Config struct {
 	Log          LogConfig          `mapstructure:"log"`
 	Auth         AuthConfig         `mapstructure:"auth"`
 	MessageQueue MessageQueueConfig `mapstructure:"message_queue"`
+	GBT32960     GBT32960Config     `mapstructure:"gbt32960"`
+}
+
+// GBT32960Config GB/T 32960 协议处理相关配置
+type GBT32960Config struct {
+	TimeCalibration TimeCalibrationConfig `mapstructure:"time_calibration"`
+	Session         SessionConfig         `mapstructure:"session"`
+	Crypto          CryptoConfig          `mapstructure:"crypto"`
+	CustomTypes     CustomTypesConfig     `mapstructure:"custom_types"`
+	CellAssembly    CellAssemblyConfig    `mapstructure:"cell_assembly"`
+	Location        LocationConfig        `mapstructure:"location"`
+	CollectTime     CollectTimeConfig     `mapstructure:"collect_time"`
+	VINValidation   VINValidationConfig   `mapstructure:"vin_validation"`
+	Decode          DecodeConfig          `mapstructure:"decode"`
+}
+
+// DecodeConfig 实时数据解码模式
+type DecodeConfig struct {
+	// Mode strict (任一信息单元异常即拒绝整包) / lenient (发布部分数据并附诊断信息), 默认 strict
+	Mode      string                 `mapstructure:"mode"`
+	Platforms []PlatformDecodeConfig `mapstructure:"platforms"` // 按平台登入账号覆盖
+}
+
+// PlatformDecodeConfig 单个平台账号的解码模式
+type PlatformDecodeConfig struct {
+	Username string `mapstructure:"username"`
+	Mode     string `mapstructure:"mode"`
+}
+
+// VINValidationConfig 车辆登入与实时数据的 VIN 校验 (长度、字符集、第 9 位校验码)
+type VINValidationConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Action VIN 无效时的处理: reject (拒绝报文), quarantine (投递到隔离 Topic), tag (正常投递并标记)
+	Action               string `mapstructure:"action"`
+	QuarantineTopic      string `mapstructure:"quarantine_topic"`       // 隔离 Topic (Kafka)
+	QuarantineRoutingKey string `mapstructure:"quarantine_routing_key"` // 隔离路由键 (RabbitMQ)
+}
+
+// CollectTimeConfig 采集时间解析与校验配置
+type CollectTimeConfig struct {
+	TimeZone  string        `mapstructure:"time_zone"`  // IANA 时区名 (如 Asia/Shanghai), 为空时为 GMT+8
+	MaxFuture time.Duration `mapstructure:"max_future"` // 采集时间超前接收时间的容许值, 超过标记为 FUTURE (默认 5m, 0 不检查)
+	MaxPast   time.Duration `mapstructure:"max_past"`   // 实时数据采集时间滞后的容许值, 超过标记为 STALE (默认 24h, 0 不检查, 补发数据不检查)
+}
+
+// LocationConfig 车辆位置数据 (0x05) 输出配置
+type LocationConfig struct {
+	// CoordinateSystems 除 WGS-84 外附加输出的坐标系: gcj02 (高德/腾讯), bd09 (百度)
+	CoordinateSystems []string `mapstructure:"coordinate_systems"`
+}
+
+// CellAssemblyConfig 分帧上报的单体电压 (2016 版 0x08) 拼接配置
+type CellAssemblyConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Timeout time.Duration `mapstructure:"timeout"` // 等待剩余分帧的最长时间, 超时按不完整数据发布
+}
+
+// CustomTypesConfig 车企自定义信息类型 (0x80~0xFE, 2025 版 0x09) 的布局描述
+type CustomTypesConfig struct {
+	SchemaFiles []string `mapstructure:"schema_files"` // YAML 描述文件, 每个车企一个
+}
+
+// CryptoConfig 数据单元加解密密钥配置
+// 密钥可逐条配置，也可按目录约定放置:
+// <key_dir>/vin/<VIN>/ 与 <key_dir>/platform/<平台用户名>/ 下的同名文件 (见 KeyConfig 字段注释)
+type CryptoConfig struct {
+	KeyDir string      `mapstructure:"key_dir"`
+	Keys   []KeyConfig `mapstructure:"keys"`
+}
+
+// KeyConfig 单个车辆或平台账号的密钥 (VIN 与 Platform 二选一, 按 VIN 优先查找)
+type KeyConfig struct {
+	VIN              string `mapstructure:"vin"`
+	Platform         string `mapstructure:"platform"`
+	RSAPrivateKey    string `mapstructure:"rsa_private_key"`     // 本端 RSA 私钥 PEM 文件 (目录约定: rsa_private.pem)
+	RSAPeerPublicKey string `mapstructure:"rsa_peer_public_key"` // 对端 RSA 公钥 PEM 文件 (目录约定: rsa_peer_public.pem)
+	AESKey           string `mapstructure:"aes_key"`             // AES-128 密钥, 16 字节十六进制 (目录约定: aes.key)
+	AESIV            string `mapstructure:"aes_iv"`              // AES 初始向量, 十六进制, 为空时使用 ECB (目录约定: aes.iv)
+	SM2PrivateKey    string `mapstructure:"sm2_private_key"`     // 本端 SM2 私钥 PEM 文件 (目录约定: sm2_private.pem)
+	SM2PeerPublicKey string `mapstructure:"sm2_peer_public_key"` // 对端 SM2 公钥 PEM 文件 (目录约定: sm2_peer_public.pem)
+	SM4Key           string `mapstructure:"sm4_key"`             // SM4 密钥, 16 字节十六进制 (目录约定: sm4.key)
+	SM4IV            string `mapstructure:"sm4_iv"`              // SM4 初始向量, 十六进制, 为空时使用 ECB (目录约定: sm4.iv)
+}
+
+// SessionConfig 车辆会话配置
+type SessionConfig struct {
+	// DuplicatePolicy 同一 VIN 从另一连接重复登入时的处理策略:
+	// reject (拒绝新连接, 应答 0x03), kick (踢掉旧连接), allow (允许并告警)
+	DuplicatePolicy string `mapstructure:"duplicate_policy"`
+}
+
+// TimeCalibrationConfig 终端校时配置
+type TimeCalibrationConfig struct {
+	AutoPush       bool          `mapstructure:"auto_push"`       // 检测到时钟偏差时主动下发校时
+	DriftThreshold time.Duration `mapstructure:"drift_threshold"` // 触发校时的偏差阈值
+	MinInterval    time.Duration `mapstructure:"min_interval"`    // 同一车辆两次主动校时的最小间隔
 }
 
 type MessageQueueConfig struct {
@@ -54,10 +152,20 @@ type UserConfig struct {
 	Password string `mapstructure:"password"`
 }
 
+// 采集时间校验的默认容许值
+const (
+	DefaultMaxFuture = 5 * time.Minute
+	DefaultMaxPast   = 24 * time.Hour
+)
+
 func LoadConfig(path string) (*Config, error) {
 	viper.SetConfigFile(path)
 	viper.AutomaticEnv()
 
+	// 采集时间校验默认开启, 显式配置为 0 时关闭
+	viper.SetDefault("gbt32960.collect_time.max_future", DefaultMaxFuture)
+	viper.SetDefault("gbt32960.collect_time.max_past", DefaultMaxPast)
+
 	if err := viper.ReadInConfig(); err != nil {
 		return nil, err
 	}
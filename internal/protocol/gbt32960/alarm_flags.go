@@ -0,0 +1,87 @@
+package gbt32960
+
+import "fmt"
+
+// AlarmFlag 通用报警标志位定义
+type AlarmFlag struct {
+	Bit  uint   // 位序号 (0 为最低位)
+	Name string // 标志名称 (MQ 中使用)
+	Desc string // 标准中的名称
+}
+
+// alarmFlags2016 GB/T 32960.3-2016 表 18 通用报警标志 (位 19~31 预留)
+var alarmFlags2016 = []AlarmFlag{
+	{0, "TEMP_DIFF", "温度差异报警"},
+	{1, "BATTERY_HIGH_TEMP", "电池高温报警"},
+	{2, "STORAGE_OVER_VOLTAGE", "车载储能装置类型过压报警"},
+	{3, "STORAGE_UNDER_VOLTAGE", "车载储能装置类型欠压报警"},
+	{4, "SOC_LOW", "SOC 低报警"},
+	{5, "CELL_OVER_VOLTAGE", "单体电池过压报警"},
+	{6, "CELL_UNDER_VOLTAGE", "单体电池欠压报警"},
+	{7, "SOC_HIGH", "SOC 过高报警"},
+	{8, "SOC_JUMP", "SOC 跳变报警"},
+	{9, "STORAGE_MISMATCH", "可充电储能系统不匹配报警"},
+	{10, "CELL_CONSISTENCY", "电池单体一致性差报警"},
+	{11, "INSULATION", "绝缘报警"},
+	{12, "DCDC_TEMP", "DC-DC 温度报警"},
+	{13, "BRAKE_SYSTEM", "制动系统报警"},
+	{14, "DCDC_STATUS", "DC-DC 状态报警"},
+	{15, "MOTOR_CTRL_TEMP", "驱动电机控制器温度报警"},
+	{16, "HV_INTERLOCK", "高压互锁状态报警"},
+	{17, "MOTOR_TEMP", "驱动电机温度报警"},
+	{18, "STORAGE_OVERCHARGE", "车载储能装置类型过充报警"},
+}
+
+// alarmFlags2025 GB/T 32960.3-2025 通用报警标志
+// 2025 版以动力蓄电池包替代可充电储能装置的表述，并增加电池热事件报警
+var alarmFlags2025 = []AlarmFlag{
+	{0, "TEMP_DIFF", "温度差异报警"},
+	{1, "BATTERY_HIGH_TEMP", "电池高温报警"},
+	{2, "PACK_OVER_VOLTAGE", "动力蓄电池包过压报警"},
+	{3, "PACK_UNDER_VOLTAGE", "动力蓄电池包欠压报警"},
+	{4, "SOC_LOW", "SOC 低报警"},
+	{5, "CELL_OVER_VOLTAGE", "单体电池过压报警"},
+	{6, "CELL_UNDER_VOLTAGE", "单体电池欠压报警"},
+	{7, "SOC_HIGH", "SOC 过高报警"},
+	{8, "SOC_JUMP", "SOC 跳变报警"},
+	{9, "PACK_MISMATCH", "动力蓄电池包不匹配报警"},
+	{10, "CELL_CONSISTENCY", "电池单体一致性差报警"},
+	{11, "INSULATION", "绝缘报警"},
+	{12, "DCDC_TEMP", "DC-DC 温度报警"},
+	{13, "BRAKE_SYSTEM", "制动系统报警"},
+	{14, "DCDC_STATUS", "DC-DC 状态报警"},
+	{15, "MOTOR_CTRL_TEMP", "驱动电机控制器温度报警"},
+	{16, "HV_INTERLOCK", "高压互锁状态报警"},
+	{17, "MOTOR_TEMP", "驱动电机温度报警"},
+	{18, "PACK_OVERCHARGE", "动力蓄电池包过充报警"},
+	{19, "BATTERY_THERMAL_EVENT", "电池热事件报警"},
+}
+
+// AlarmFlags 返回协议版本对应的通用报警标志位表
+func AlarmFlags(version ProtocolVersion) []AlarmFlag {
+	if version == Version2025 {
+		return alarmFlags2025
+	}
+	return alarmFlags2016
+}
+
+// DecodeAlarmMask 将通用报警标志展开为已置位的标志名称
+// 标准未定义 (预留) 的位以 BIT_n 表示
+func DecodeAlarmMask(version ProtocolVersion, mask uint32) []string {
+	flags := AlarmFlags(version)
+	active := make([]string, 0)
+	for bit := uint(0); bit < 32; bit++ {
+		if mask&(1<<bit) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("BIT_%d", bit)
+		for _, f := range flags {
+			if f.Bit == bit {
+				name = f.Name
+				break
+			}
+		}
+		active = append(active, name)
+	}
+	return active
+}
@@ -0,0 +1,79 @@
+package gbt32960
+
+import "math"
+
+// 坐标系名称
+const (
+	CoordWGS84 = "wgs84"
+	CoordGCJ02 = "gcj02" // 国测局坐标 (高德、腾讯地图)
+	CoordBD09  = "bd09"  // 百度坐标
+)
+
+// Coordinate 经纬度坐标
+type Coordinate struct {
+	Longitude float64
+	Latitude  float64
+}
+
+// GCJ-02 偏移算法参数 (克拉索夫斯基椭球)
+const (
+	krasovskyA  = 6378245.0
+	krasovskyEE = 0.00669342162296594323
+	bdXPi       = math.Pi * 3000.0 / 180.0
+)
+
+// WGS84ToGCJ02 WGS-84 坐标转国测局坐标，中国境外不做偏移
+func WGS84ToGCJ02(lng, lat float64) Coordinate {
+	if outOfChina(lng, lat) {
+		return Coordinate{Longitude: lng, Latitude: lat}
+	}
+	dLat := transformLat(lng-105.0, lat-35.0)
+	dLng := transformLng(lng-105.0, lat-35.0)
+	radLat := lat / 180.0 * math.Pi
+	magic := math.Sin(radLat)
+	magic = 1 - krasovskyEE*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((krasovskyA * (1 - krasovskyEE)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (krasovskyA / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return Coordinate{Longitude: round6(lng + dLng), Latitude: round6(lat + dLat)}
+}
+
+// GCJ02ToBD09 国测局坐标转百度坐标
+func GCJ02ToBD09(lng, lat float64) Coordinate {
+	z := math.Sqrt(lng*lng+lat*lat) + 0.00002*math.Sin(lat*bdXPi)
+	theta := math.Atan2(lat, lng) + 0.000003*math.Cos(lng*bdXPi)
+	return Coordinate{
+		Longitude: round6(z*math.Cos(theta) + 0.0065),
+		Latitude:  round6(z*math.Sin(theta) + 0.006),
+	}
+}
+
+// WGS84ToBD09 WGS-84 坐标转百度坐标
+func WGS84ToBD09(lng, lat float64) Coordinate {
+	gcj := WGS84ToGCJ02(lng, lat)
+	return GCJ02ToBD09(gcj.Longitude, gcj.Latitude)
+}
+
+func outOfChina(lng, lat float64) bool {
+	return lng < 72.004 || lng > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+func transformLat(x, y float64) float64 {
+	ret := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	return ret
+}
+
+func transformLng(x, y float64) float64 {
+	ret := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+	return ret
+}
+
+func round6(v float64) float64 {
+	return math.Round(v*1e6) / 1e6
+}
@@ -0,0 +1,227 @@
+package gbt32960
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// 数据单元加密方式 (报文头加密方式字段)
+const (
+	EncryptionNone     byte = 0x01 // 不加密
+	EncryptionRSA      byte = 0x02 // RSA 加密
+	EncryptionAES128   byte = 0x03 // AES128 加密
+	EncryptionSM2      byte = 0x04 // SM2 加密 (2025)
+	EncryptionSM4      byte = 0x05 // SM4 加密 (2025)
+	EncryptionAbnormal byte = 0xFE // 异常
+	EncryptionInvalid  byte = 0xFF // 无效
+)
+
+var (
+	// ErrNoKey 缺少对应的密钥
+	ErrNoKey = errors.New("缺少密钥")
+	// ErrUnsupportedEncryption 不支持的加密方式
+	ErrUnsupportedEncryption = errors.New("不支持的加密方式")
+)
+
+// IsEncrypted 判断加密方式是否需要对数据单元加解密
+func IsEncrypted(enc byte) bool {
+	switch enc {
+	case EncryptionRSA, EncryptionAES128, EncryptionSM2, EncryptionSM4:
+		return true
+	default:
+		return false
+	}
+}
+
+// Cipher 数据单元加解密
+// Decrypt 处理对端上行的密文，Encrypt 生成发往对端的密文
+type Cipher interface {
+	Encrypt(plain []byte) ([]byte, error)
+	Decrypt(cipherText []byte) ([]byte, error)
+}
+
+// RSACipher RSA 数据单元加解密 (PKCS#1 v1.5, 按密钥长度分块)
+// 使用本端私钥解密上行数据，使用对端公钥加密下行数据
+type RSACipher struct {
+	Private    *rsa.PrivateKey // 本端私钥
+	PeerPublic *rsa.PublicKey  // 对端公钥
+}
+
+// Decrypt 分块解密，每块长度等于私钥模长
+func (c *RSACipher) Decrypt(cipherText []byte) ([]byte, error) {
+	if c.Private == nil {
+		return nil, fmt.Errorf("%w: RSA 私钥", ErrNoKey)
+	}
+	blockSize := c.Private.Size()
+	if len(cipherText) == 0 || len(cipherText)%blockSize != 0 {
+		return nil, fmt.Errorf("RSA 密文长度 %d 不是分块长度 %d 的整数倍", len(cipherText), blockSize)
+	}
+
+	plain := make([]byte, 0, len(cipherText))
+	for off := 0; off < len(cipherText); off += blockSize {
+		block, err := rsa.DecryptPKCS1v15(rand.Reader, c.Private, cipherText[off:off+blockSize])
+		if err != nil {
+			return nil, fmt.Errorf("RSA 解密失败: %v", err)
+		}
+		plain = append(plain, block...)
+	}
+	return plain, nil
+}
+
+// Encrypt 分块加密，每块明文最长为公钥模长 - 11
+func (c *RSACipher) Encrypt(plain []byte) ([]byte, error) {
+	if c.PeerPublic == nil {
+		return nil, fmt.Errorf("%w: RSA 对端公钥", ErrNoKey)
+	}
+	chunk := c.PeerPublic.Size() - 11
+
+	var out []byte
+	for off := 0; off < len(plain); off += chunk {
+		end := off + chunk
+		if end > len(plain) {
+			end = len(plain)
+		}
+		block, err := rsa.EncryptPKCS1v15(rand.Reader, c.PeerPublic, plain[off:end])
+		if err != nil {
+			return nil, fmt.Errorf("RSA 加密失败: %v", err)
+		}
+		out = append(out, block...)
+	}
+	return out, nil
+}
+
+// AESCipher AES-128 数据单元加解密 (PKCS#7 填充)
+// 配置了 IV 时使用 CBC 模式，否则使用 ECB 模式
+type AESCipher struct {
+	Key []byte // 16 字节密钥
+	IV  []byte // 16 字节初始向量 (可选)
+}
+
+// NewAESCipher 校验密钥与初始向量长度
+func NewAESCipher(key, iv []byte) (*AESCipher, error) {
+	if len(key) != 16 {
+		return nil, fmt.Errorf("AES-128 密钥长度应为 16 字节, 实际 %d", len(key))
+	}
+	if len(iv) != 0 && len(iv) != aes.BlockSize {
+		return nil, fmt.Errorf("AES 初始向量长度应为 %d 字节, 实际 %d", aes.BlockSize, len(iv))
+	}
+	return &AESCipher{Key: key, IV: iv}, nil
+}
+
+// Encrypt 填充后加密
+func (c *AESCipher) Encrypt(plain []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	return encryptBlocks(block, c.IV, pkcs7Pad(plain, block.BlockSize())), nil
+}
+
+// Decrypt 解密后去除填充
+func (c *AESCipher) Decrypt(cipherText []byte) ([]byte, error) {
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := decryptBlocks(block, c.IV, cipherText)
+	if err != nil {
+		return nil, fmt.Errorf("AES 解密失败: %v", err)
+	}
+	return plain, nil
+}
+
+// encryptBlocks 分组加密: iv 为空时按 ECB，否则按 CBC
+func encryptBlocks(block cipher.Block, iv, padded []byte) []byte {
+	out := make([]byte, len(padded))
+	if len(iv) > 0 {
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, padded)
+		return out
+	}
+	bs := block.BlockSize()
+	for off := 0; off < len(padded); off += bs {
+		block.Encrypt(out[off:off+bs], padded[off:off+bs])
+	}
+	return out
+}
+
+// decryptBlocks 分组解密并去除 PKCS#7 填充
+func decryptBlocks(block cipher.Block, iv, cipherText []byte) ([]byte, error) {
+	bs := block.BlockSize()
+	if len(cipherText) == 0 || len(cipherText)%bs != 0 {
+		return nil, fmt.Errorf("密文长度 %d 不是分组长度 %d 的整数倍", len(cipherText), bs)
+	}
+	out := make([]byte, len(cipherText))
+	if len(iv) > 0 {
+		cipher.NewCBCDecrypter(block, iv).CryptBlocks(out, cipherText)
+	} else {
+		for off := 0; off < len(cipherText); off += bs {
+			block.Decrypt(out[off:off+bs], cipherText[off:off+bs])
+		}
+	}
+	return pkcs7Unpad(out, bs)
+}
+
+func pkcs7Pad(data []byte, blockSize int) []byte {
+	n := blockSize - len(data)%blockSize
+	return append(append([]byte{}, data...), bytes.Repeat([]byte{byte(n)}, n)...)
+}
+
+func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
+	n := int(data[len(data)-1])
+	if n == 0 || n > blockSize || n > len(data) {
+		return nil, errors.New("填充无效")
+	}
+	for _, b := range data[len(data)-n:] {
+		if int(b) != n {
+			return nil, errors.New("填充无效")
+		}
+	}
+	return data[:len(data)-n], nil
+}
+
+// ParseRSAPrivateKey 解析 PEM 格式的 RSA 私钥 (PKCS#1 或 PKCS#8)
+func ParseRSAPrivateKey(pemData []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("无效的 PEM 数据")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析 RSA 私钥失败: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("PEM 数据不是 RSA 私钥")
+	}
+	return rsaKey, nil
+}
+
+// ParseRSAPublicKey 解析 PEM 格式的 RSA 公钥 (PKIX 或 PKCS#1)
+func ParseRSAPublicKey(pemData []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("无效的 PEM 数据")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析 RSA 公钥失败: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("PEM 数据不是 RSA 公钥")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,140 @@
+package gbt32960
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm4"
+	"github.com/emmansun/gmsm/smx509"
+)
+
+// SM2Cipher SM2 数据单元加解密 (GB/T 32918.4, 密文格式 C1C3C2, C1 为非压缩点)
+// 使用本端私钥解密上行数据，使用对端公钥加密下行数据
+type SM2Cipher struct {
+	Private    *sm2.PrivateKey  // 本端私钥
+	PeerPublic *ecdsa.PublicKey // 对端公钥
+}
+
+// Decrypt 解密 C1C3C2 格式密文
+func (c *SM2Cipher) Decrypt(cipherText []byte) ([]byte, error) {
+	if c.Private == nil {
+		return nil, fmt.Errorf("%w: SM2 私钥", ErrNoKey)
+	}
+	plain, err := sm2.Decrypt(c.Private, cipherText)
+	if err != nil {
+		return nil, fmt.Errorf("SM2 解密失败: %v", err)
+	}
+	return plain, nil
+}
+
+// Encrypt 加密为 C1C3C2 格式密文
+func (c *SM2Cipher) Encrypt(plain []byte) ([]byte, error) {
+	if c.PeerPublic == nil {
+		return nil, fmt.Errorf("%w: SM2 对端公钥", ErrNoKey)
+	}
+	out, err := sm2.Encrypt(rand.Reader, c.PeerPublic, plain, nil)
+	if err != nil {
+		return nil, fmt.Errorf("SM2 加密失败: %v", err)
+	}
+	return out, nil
+}
+
+// SM4Cipher SM4 数据单元加解密 (PKCS#7 填充)
+// 配置了 IV 时使用 CBC 模式，否则使用 ECB 模式
+type SM4Cipher struct {
+	Key []byte // 16 字节密钥
+	IV  []byte // 16 字节初始向量 (可选)
+}
+
+// NewSM4Cipher 校验密钥与初始向量长度
+func NewSM4Cipher(key, iv []byte) (*SM4Cipher, error) {
+	if len(key) != sm4.BlockSize {
+		return nil, fmt.Errorf("SM4 密钥长度应为 %d 字节, 实际 %d", sm4.BlockSize, len(key))
+	}
+	if len(iv) != 0 && len(iv) != sm4.BlockSize {
+		return nil, fmt.Errorf("SM4 初始向量长度应为 %d 字节, 实际 %d", sm4.BlockSize, len(iv))
+	}
+	return &SM4Cipher{Key: key, IV: iv}, nil
+}
+
+// Encrypt 填充后加密
+func (c *SM4Cipher) Encrypt(plain []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	return encryptBlocks(block, c.IV, pkcs7Pad(plain, block.BlockSize())), nil
+}
+
+// Decrypt 解密后去除填充
+func (c *SM4Cipher) Decrypt(cipherText []byte) ([]byte, error) {
+	block, err := sm4.NewCipher(c.Key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := decryptBlocks(block, c.IV, cipherText)
+	if err != nil {
+		return nil, fmt.Errorf("SM4 解密失败: %v", err)
+	}
+	return plain, nil
+}
+
+// GenerateSM2Key 生成 SM2 密钥对，返回 PKCS#8 私钥与 PKIX 公钥的 PEM 数据
+func GenerateSM2Key() (privatePEM, publicPEM []byte, err error) {
+	key, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	privDER, err := smx509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	pubDER, err := smx509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	privatePEM = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+	return privatePEM, publicPEM, nil
+}
+
+// ParseSM2PrivateKey 解析 PEM 格式的 SM2 私钥 (PKCS#8 或 SEC1)
+func ParseSM2PrivateKey(pemData []byte) (*sm2.PrivateKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("无效的 PEM 数据")
+	}
+	if key, err := smx509.ParseSM2PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := smx509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析 SM2 私钥失败: %v", err)
+	}
+	sm2Key, ok := key.(*sm2.PrivateKey)
+	if !ok {
+		return nil, errors.New("PEM 数据不是 SM2 私钥")
+	}
+	return sm2Key, nil
+}
+
+// ParseSM2PublicKey 解析 PEM 格式的 SM2 公钥 (PKIX)
+func ParseSM2PublicKey(pemData []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return nil, errors.New("无效的 PEM 数据")
+	}
+	key, err := smx509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("解析 SM2 公钥失败: %v", err)
+	}
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok || pub.Curve != sm2.P256() {
+		return nil, errors.New("PEM 数据不是 SM2 公钥")
+	}
+	return pub, nil
+}
@@ -0,0 +1,128 @@
+package gbt32960
+
+import (
+	"bytes"
+	"testing"
+)
+
+// newSM2Pair 生成网关与终端两端的 SM2 密钥 (与 cmd/keygen 相同), 返回网关端与终端端的加解密器
+func newSM2Pair(t *testing.T) (gateway, terminal *SM2Cipher) {
+	t.Helper()
+	gwPrivPEM, gwPubPEM, err := GenerateSM2Key()
+	if err != nil {
+		t.Fatalf("生成网关密钥: %v", err)
+	}
+	termPrivPEM, termPubPEM, err := GenerateSM2Key()
+	if err != nil {
+		t.Fatalf("生成终端密钥: %v", err)
+	}
+
+	gwPriv, err := ParseSM2PrivateKey(gwPrivPEM)
+	if err != nil {
+		t.Fatalf("解析网关私钥: %v", err)
+	}
+	gwPub, err := ParseSM2PublicKey(gwPubPEM)
+	if err != nil {
+		t.Fatalf("解析网关公钥: %v", err)
+	}
+	termPriv, err := ParseSM2PrivateKey(termPrivPEM)
+	if err != nil {
+		t.Fatalf("解析终端私钥: %v", err)
+	}
+	termPub, err := ParseSM2PublicKey(termPubPEM)
+	if err != nil {
+		t.Fatalf("解析终端公钥: %v", err)
+	}
+	return &SM2Cipher{Private: gwPriv, PeerPublic: termPub}, &SM2Cipher{Private: termPriv, PeerPublic: gwPub}
+}
+
+func TestSM2RoundTrip(t *testing.T) {
+	gateway, terminal := newSM2Pair(t)
+	plain := []byte{0x1A, 0x0A, 0x10, 0x08, 0x1E, 0x00, 0x01, 0x01, 0x03, 0x01}
+
+	// 上行: 终端用网关公钥加密, 网关用私钥解密
+	up, err := terminal.Encrypt(plain)
+	if err != nil {
+		t.Fatalf("上行加密: %v", err)
+	}
+	if up[0] != 0x04 {
+		t.Errorf("C1 应为非压缩点 (0x04 开头), 实际 %#x", up[0])
+	}
+	got, err := gateway.Decrypt(up)
+	if err != nil {
+		t.Fatalf("上行解密: %v", err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Errorf("上行明文 = %x, 期望 %x", got, plain)
+	}
+
+	// 下行: 网关用终端公钥加密, 终端解密
+	down, err := gateway.Encrypt(plain)
+	if err != nil {
+		t.Fatalf("下行加密: %v", err)
+	}
+	if got, err = terminal.Decrypt(down); err != nil || !bytes.Equal(got, plain) {
+		t.Errorf("下行解密 = %x, %v, 期望 %x", got, err, plain)
+	}
+
+	// 网关不能解密发给终端的密文
+	if _, err := gateway.Decrypt(down); err == nil {
+		t.Error("使用错误私钥解密应失败")
+	}
+}
+
+func TestSM2MissingKey(t *testing.T) {
+	c := &SM2Cipher{}
+	if _, err := c.Encrypt([]byte{1}); err == nil {
+		t.Error("缺少对端公钥时加密应失败")
+	}
+	if _, err := c.Decrypt([]byte{1}); err == nil {
+		t.Error("缺少私钥时解密应失败")
+	}
+}
+
+func TestSM4RoundTrip(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	iv := []byte("fedcba9876543210")
+	tests := []struct {
+		name  string
+		iv    []byte
+		plain []byte
+	}{
+		{"ECB", nil, []byte("GB/T 32960 data unit")},
+		{"CBC", iv, []byte("GB/T 32960 data unit")},
+		{"CBC/整块", iv, bytes.Repeat([]byte{0xAB}, 32)},
+		{"ECB/空", nil, []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewSM4Cipher(key, tt.iv)
+			if err != nil {
+				t.Fatalf("NewSM4Cipher: %v", err)
+			}
+			cipherText, err := c.Encrypt(tt.plain)
+			if err != nil {
+				t.Fatalf("加密: %v", err)
+			}
+			if len(cipherText)%16 != 0 || len(cipherText) <= len(tt.plain) {
+				t.Errorf("密文长度 %d 不是含填充的整块", len(cipherText))
+			}
+			got, err := c.Decrypt(cipherText)
+			if err != nil {
+				t.Fatalf("解密: %v", err)
+			}
+			if !bytes.Equal(got, tt.plain) {
+				t.Errorf("明文 = %x, 期望 %x", got, tt.plain)
+			}
+		})
+	}
+}
+
+func TestNewSM4CipherRejectsBadLength(t *testing.T) {
+	if _, err := NewSM4Cipher([]byte("short"), nil); err == nil {
+		t.Error("密钥长度错误应返回错误")
+	}
+	if _, err := NewSM4Cipher([]byte("0123456789abcdef"), []byte("short")); err == nil {
+		t.Error("初始向量长度错误应返回错误")
+	}
+}
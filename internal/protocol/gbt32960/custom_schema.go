@@ -0,0 +1,329 @@
+package gbt32960
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// 自定义信息类型 (0x80~0xFE, 2025 版另有 0x09) 的数据块格式: [信息类型标志 1][数据长度 2][数据 N]
+// 数据内容由车企自定义，通过 YAML 描述的二进制布局解析为命名字段
+
+// 字段类型
+const (
+	FieldUint8  = "uint8"
+	FieldUint16 = "uint16"
+	FieldUint32 = "uint32"
+	FieldInt8   = "int8"
+	FieldInt16  = "int16"
+	FieldInt32  = "int32"
+	FieldBytes  = "bytes"  // 定长字节, 输出十六进制字符串
+	FieldString = "string" // 定长字符串, 去除尾部 0
+	FieldSkip   = "skip"   // 保留字节, 不输出
+	FieldGroup  = "group"  // 重复字段组
+)
+
+// CustomSchema 单个车企的自定义信息类型描述 (一个 YAML 文件)
+// VINPrefixes 与 Platforms 均为空时对所有车辆生效
+type CustomSchema struct {
+	OEM         string             `yaml:"oem"`
+	VINPrefixes []string           `yaml:"vin_prefixes"` // 适用的 VIN 前缀 (如 WMI)
+	Platforms   []string           `yaml:"platforms"`    // 适用的平台登入账号
+	Types       []CustomTypeSchema `yaml:"types"`
+}
+
+// CustomTypeSchema 单个自定义信息类型的布局
+type CustomTypeSchema struct {
+	InfoType    byte          `yaml:"info_type"`    // 信息类型标志
+	Version     string        `yaml:"version"`      // 适用协议版本: "2016" / "2025", 为空时两者均适用
+	MessageType string        `yaml:"message_type"` // 分发消息类型, 为空时为 CUSTOM_XX
+	Fields      []FieldSchema `yaml:"fields"`
+}
+
+// FieldSchema 字段布局
+// 数值字段输出 原始值*Scale+Offset (两者均未配置时输出整数原值)
+type FieldSchema struct {
+	Name       string        `yaml:"name"`
+	Type       string        `yaml:"type"`
+	Width      int           `yaml:"width"`       // bytes / string / skip 的字节数
+	Scale      float64       `yaml:"scale"`       // 精度
+	Offset     float64       `yaml:"offset"`      // 偏移量
+	Endian     string        `yaml:"endian"`      // big (默认) / little
+	Count      int           `yaml:"count"`       // group 的固定重复次数
+	CountField string        `yaml:"count_field"` // group 的重复次数取自同一层级中先出现的整数字段
+	Fields     []FieldSchema `yaml:"fields"`      // group 的成员字段
+}
+
+// CustomDecoder 按车企描述解析自定义信息类型
+type CustomDecoder struct {
+	schemas []*CustomSchema
+}
+
+// LoadCustomSchemas 加载并校验自定义信息类型描述文件
+func LoadCustomSchemas(paths ...string) (*CustomDecoder, error) {
+	d := &CustomDecoder{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取自定义类型描述失败: %v", err)
+		}
+		schema := &CustomSchema{}
+		if err := yaml.Unmarshal(data, schema); err != nil {
+			return nil, fmt.Errorf("解析自定义类型描述 %s 失败: %v", path, err)
+		}
+		if err := schema.validate(); err != nil {
+			return nil, fmt.Errorf("自定义类型描述 %s 无效: %v", path, err)
+		}
+		d.schemas = append(d.schemas, schema)
+	}
+	return d, nil
+}
+
+// IsCustomType 判断信息类型是否为自定义数据 (带 2 字节长度前缀)
+func IsCustomType(version ProtocolVersion, infoType byte) bool {
+	if infoType >= 0x80 && infoType <= 0xFE {
+		return true
+	}
+	return version == Version2025 && infoType == 0x09
+}
+
+// SplitCustomBlock 从信息类型标志之后的数据中取出自定义数据块，返回数据与占用的字节数
+func SplitCustomBlock(data []byte) ([]byte, int, error) {
+	if len(data) < 2 {
+		return nil, 0, errShort("自定义数据长度字段不足", 0, 2, len(data))
+	}
+	n := int(binary.BigEndian.Uint16(data[0:2]))
+	if len(data) < 2+n {
+		return nil, 0, errShort("自定义数据长度不足", 2, n, len(data)-2)
+	}
+	return data[2 : 2+n], 2 + n, nil
+}
+
+// Lookup 查找适用于车辆的自定义类型描述，VIN 前缀或平台账号匹配的描述优先于通用描述
+func (d *CustomDecoder) Lookup(version ProtocolVersion, infoType byte, vin, platform string) (*CustomTypeSchema, bool) {
+	if d == nil {
+		return nil, false
+	}
+	var fallback *CustomTypeSchema
+	for _, s := range d.schemas {
+		t := s.typeFor(version, infoType)
+		if t == nil {
+			continue
+		}
+		if len(s.VINPrefixes) == 0 && len(s.Platforms) == 0 {
+			if fallback == nil {
+				fallback = t
+			}
+			continue
+		}
+		if s.matches(vin, platform) {
+			return t, true
+		}
+	}
+	return fallback, fallback != nil
+}
+
+// Decode 按布局解析自定义数据块
+func (t *CustomTypeSchema) Decode(data []byte) (map[string]interface{}, error) {
+	out, n, err := decodeFields(t.Fields, data)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(data) {
+		out["_trailing"] = hex.EncodeToString(data[n:])
+	}
+	return out, nil
+}
+
+// Message 分发消息类型
+func (t *CustomTypeSchema) Message() string {
+	if t.MessageType != "" {
+		return t.MessageType
+	}
+	return fmt.Sprintf("CUSTOM_%02X", t.InfoType)
+}
+
+func (s *CustomSchema) typeFor(version ProtocolVersion, infoType byte) *CustomTypeSchema {
+	for i := range s.Types {
+		t := &s.Types[i]
+		if t.InfoType != infoType {
+			continue
+		}
+		switch t.Version {
+		case "":
+			return t
+		case "2016":
+			if version == Version2016 {
+				return t
+			}
+		case "2025":
+			if version == Version2025 {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+func (s *CustomSchema) matches(vin, platform string) bool {
+	for _, p := range s.VINPrefixes {
+		if strings.HasPrefix(vin, p) {
+			return true
+		}
+	}
+	for _, p := range s.Platforms {
+		if platform != "" && p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *CustomSchema) validate() error {
+	for _, t := range s.Types {
+		if t.Version != "" && t.Version != "2016" && t.Version != "2025" {
+			return fmt.Errorf("信息类型 0x%02X 的协议版本无效: %s", t.InfoType, t.Version)
+		}
+		custom := (t.Version != "2025" && IsCustomType(Version2016, t.InfoType)) ||
+			(t.Version != "2016" && IsCustomType(Version2025, t.InfoType))
+		if !custom {
+			return fmt.Errorf("信息类型 0x%02X 不是自定义类型", t.InfoType)
+		}
+		if err := validateFields(t.Fields); err != nil {
+			return fmt.Errorf("信息类型 0x%02X: %v", t.InfoType, err)
+		}
+	}
+	return nil
+}
+
+func validateFields(fields []FieldSchema) error {
+	integers := make(map[string]bool)
+	for _, f := range fields {
+		if f.Name == "" && f.Type != FieldSkip {
+			return errors.New("字段缺少 name")
+		}
+		if f.Endian != "" && f.Endian != "big" && f.Endian != "little" {
+			return fmt.Errorf("字段 %s 的字节序无效: %s", f.Name, f.Endian)
+		}
+		switch f.Type {
+		case FieldUint8, FieldUint16, FieldUint32, FieldInt8, FieldInt16, FieldInt32:
+			integers[f.Name] = true
+		case FieldBytes, FieldString, FieldSkip:
+			if f.Width <= 0 {
+				return fmt.Errorf("字段 %s 缺少 width", f.Name)
+			}
+		case FieldGroup:
+			if f.CountField == "" && f.Count <= 0 {
+				return fmt.Errorf("字段组 %s 缺少 count 或 count_field", f.Name)
+			}
+			if f.CountField != "" && !integers[f.CountField] {
+				return fmt.Errorf("字段组 %s 的 count_field %s 须为之前出现的整数字段", f.Name, f.CountField)
+			}
+			if err := validateFields(f.Fields); err != nil {
+				return fmt.Errorf("字段组 %s: %v", f.Name, err)
+			}
+		default:
+			return fmt.Errorf("字段 %s 的类型无效: %s", f.Name, f.Type)
+		}
+	}
+	return nil
+}
+
+// decodeFields 依次解析字段，返回字段值与占用的字节数
+func decodeFields(fields []FieldSchema, data []byte) (map[string]interface{}, int, error) {
+	out := make(map[string]interface{}, len(fields))
+	counts := make(map[string]int64)
+	offset := 0
+
+	for _, f := range fields {
+		if f.Type == FieldGroup {
+			n := int64(f.Count)
+			if f.CountField != "" {
+				n = counts[f.CountField]
+			}
+			if n < 0 || n > int64(len(data)-offset) {
+				// 每个成员至少占 1 字节，重复次数不可能超过剩余长度
+				return nil, 0, fmt.Errorf("字段组 %s 重复次数无效: %d", f.Name, n)
+			}
+			items := make([]map[string]interface{}, 0, n)
+			for i := int64(0); i < n; i++ {
+				item, used, err := decodeFields(f.Fields, data[offset:])
+				if err != nil {
+					return nil, 0, fmt.Errorf("%s[%d]: %v", f.Name, i, err)
+				}
+				items = append(items, item)
+				offset += used
+			}
+			out[f.Name] = items
+			continue
+		}
+
+		width := f.width()
+		if len(data) < offset+width {
+			return nil, 0, fmt.Errorf("字段 %s 数据长度不足", f.Name)
+		}
+		raw := data[offset : offset+width]
+		offset += width
+
+		switch f.Type {
+		case FieldSkip:
+		case FieldBytes:
+			out[f.Name] = hex.EncodeToString(raw)
+		case FieldString:
+			out[f.Name] = string(trimNulls(raw))
+		default:
+			v := f.integer(raw)
+			counts[f.Name] = v
+			if f.Scale != 0 || f.Offset != 0 {
+				scale := f.Scale
+				if scale == 0 {
+					scale = 1
+				}
+				out[f.Name] = math.Round((float64(v)*scale+f.Offset)*1e6) / 1e6
+			} else {
+				out[f.Name] = v
+			}
+		}
+	}
+	return out, offset, nil
+}
+
+func (f *FieldSchema) width() int {
+	switch f.Type {
+	case FieldUint8, FieldInt8:
+		return 1
+	case FieldUint16, FieldInt16:
+		return 2
+	case FieldUint32, FieldInt32:
+		return 4
+	default:
+		return f.Width
+	}
+}
+
+func (f *FieldSchema) integer(raw []byte) int64 {
+	var order binary.ByteOrder = binary.BigEndian
+	if f.Endian == "little" {
+		order = binary.LittleEndian
+	}
+	switch f.Type {
+	case FieldUint8:
+		return int64(raw[0])
+	case FieldInt8:
+		return int64(int8(raw[0]))
+	case FieldUint16:
+		return int64(order.Uint16(raw))
+	case FieldInt16:
+		return int64(int16(order.Uint16(raw)))
+	case FieldUint32:
+		return int64(order.Uint32(raw))
+	default:
+		return int64(int32(order.Uint32(raw)))
+	}
+}
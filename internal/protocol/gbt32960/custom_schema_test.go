@@ -0,0 +1,282 @@
+package gbt32960
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCustomTypeSchemaDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  []FieldSchema
+		data    []byte
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "整数与字节序",
+			fields: []FieldSchema{
+				{Name: "u8", Type: FieldUint8},
+				{Name: "i8", Type: FieldInt8},
+				{Name: "u16", Type: FieldUint16},
+				{Name: "u16le", Type: FieldUint16, Endian: "little"},
+				{Name: "i16", Type: FieldInt16},
+				{Name: "u32le", Type: FieldUint32, Endian: "little"},
+				{Name: "i32", Type: FieldInt32},
+			},
+			data: []byte{0xFF, 0xFF, 0x01, 0x02, 0x01, 0x02, 0xFF, 0xFE, 0x01, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFD},
+			want: map[string]interface{}{
+				"u8": int64(255), "i8": int64(-1), "u16": int64(0x0102), "u16le": int64(0x0201),
+				"i16": int64(-2), "u32le": int64(1), "i32": int64(-3),
+			},
+		},
+		{
+			name: "精度与偏移",
+			fields: []FieldSchema{
+				{Name: "soh", Type: FieldUint8, Scale: 0.4},
+				{Name: "temp", Type: FieldUint8, Offset: -40},
+				{Name: "current", Type: FieldUint16, Scale: 0.1, Offset: -1000},
+			},
+			data: []byte{0xFA, 0x3C, 0x27, 0x10},
+			want: map[string]interface{}{"soh": 100.0, "temp": 20.0, "current": 0.0},
+		},
+		{
+			name: "字符串、字节与保留字节",
+			fields: []FieldSchema{
+				{Name: "code", Type: FieldString, Width: 4},
+				{Type: FieldSkip, Width: 1},
+				{Name: "raw", Type: FieldBytes, Width: 2},
+			},
+			data: []byte{'A', 'B', 0x00, 0x00, 0x99, 0xBE, 0xEF},
+			want: map[string]interface{}{"code": "AB", "raw": "beef"},
+		},
+		{
+			name: "count_field 重复字段组",
+			fields: []FieldSchema{
+				{Name: "n", Type: FieldUint8},
+				{Name: "packs", Type: FieldGroup, CountField: "n", Fields: []FieldSchema{
+					{Name: "no", Type: FieldUint8},
+					{Name: "volt", Type: FieldUint16, Scale: 0.1, Endian: "little"},
+				}},
+			},
+			data: []byte{0x02, 0x01, 0xE8, 0x03, 0x02, 0xD0, 0x07},
+			want: map[string]interface{}{
+				"n": int64(2),
+				"packs": []map[string]interface{}{
+					{"no": int64(1), "volt": 100.0},
+					{"no": int64(2), "volt": 200.0},
+				},
+			},
+		},
+		{
+			name: "固定次数字段组",
+			fields: []FieldSchema{
+				{Name: "temps", Type: FieldGroup, Count: 2, Fields: []FieldSchema{{Name: "t", Type: FieldInt8}}},
+			},
+			data: []byte{0x05, 0xFB},
+			want: map[string]interface{}{
+				"temps": []map[string]interface{}{{"t": int64(5)}, {"t": int64(-5)}},
+			},
+		},
+		{
+			name:   "多余字节",
+			fields: []FieldSchema{{Name: "a", Type: FieldUint8}},
+			data:   []byte{0x01, 0xAA, 0xBB},
+			want:   map[string]interface{}{"a": int64(1), "_trailing": "aabb"},
+		},
+		{
+			name:    "数据截断",
+			fields:  []FieldSchema{{Name: "a", Type: FieldUint8}, {Name: "b", Type: FieldUint32}},
+			data:    []byte{0x01, 0x00, 0x00},
+			wantErr: true,
+		},
+		{
+			name: "字段组成员截断",
+			fields: []FieldSchema{
+				{Name: "n", Type: FieldUint8},
+				{Name: "items", Type: FieldGroup, CountField: "n", Fields: []FieldSchema{{Name: "v", Type: FieldUint16}}},
+			},
+			data:    []byte{0x02, 0x00, 0x01, 0x00},
+			wantErr: true,
+		},
+		{
+			name: "重复次数超过剩余长度",
+			fields: []FieldSchema{
+				{Name: "n", Type: FieldUint16},
+				{Name: "items", Type: FieldGroup, CountField: "n", Fields: []FieldSchema{{Name: "v", Type: FieldUint8}}},
+			},
+			data:    []byte{0xFF, 0xFF, 0x01},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &CustomTypeSchema{InfoType: 0x80, Fields: tt.fields}
+			got, err := schema.Decode(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %v, 期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeSchema 将 YAML 写入临时文件并返回路径
+func writeSchema(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "schema.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadCustomSchemasRejectsMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		reason  string // 错误信息中应包含的内容
+	}{
+		{"YAML 语法错误", "types: [", "解析自定义类型描述"},
+		{"非自定义信息类型", "types:\n  - info_type: 0x02\n    fields: [{name: a, type: uint8}]", "不是自定义类型"},
+		{"0x09 仅 2025 版为自定义", "types:\n  - info_type: 0x09\n    version: \"2016\"\n    fields: [{name: a, type: uint8}]", "不是自定义类型"},
+		{"协议版本无效", "types:\n  - info_type: 0x80\n    version: \"2020\"\n    fields: [{name: a, type: uint8}]", "协议版本无效"},
+		{"字段类型无效", "types:\n  - info_type: 0x80\n    fields: [{name: a, type: float}]", "类型无效"},
+		{"缺少 name", "types:\n  - info_type: 0x80\n    fields: [{type: uint8}]", "缺少 name"},
+		{"缺少 width", "types:\n  - info_type: 0x80\n    fields: [{name: a, type: string}]", "缺少 width"},
+		{"字节序无效", "types:\n  - info_type: 0x80\n    fields: [{name: a, type: uint16, endian: middle}]", "字节序无效"},
+		{"字段组缺少次数", "types:\n  - info_type: 0x80\n    fields: [{name: g, type: group, fields: [{name: a, type: uint8}]}]", "缺少 count"},
+		{"count_field 在字段组之后", "types:\n  - info_type: 0x80\n    fields:\n      - {name: g, type: group, count_field: n, fields: [{name: a, type: uint8}]}\n      - {name: n, type: uint8}", "count_field"},
+		{"count_field 非整数", "types:\n  - info_type: 0x80\n    fields:\n      - {name: n, type: string, width: 1}\n      - {name: g, type: group, count_field: n, fields: [{name: a, type: uint8}]}", "count_field"},
+		{"字段组成员无效", "types:\n  - info_type: 0x80\n    fields: [{name: g, type: group, count: 1, fields: [{name: a, type: bool}]}]", "字段组 g"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadCustomSchemas(writeSchema(t, tt.content))
+			if err == nil {
+				t.Fatal("无效的描述应在加载时报错")
+			}
+			if !strings.Contains(err.Error(), tt.reason) {
+				t.Errorf("错误 = %v, 应包含 %q", err, tt.reason)
+			}
+		})
+	}
+
+	if _, err := LoadCustomSchemas(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("描述文件不存在时应报错")
+	}
+}
+
+func TestCustomDecoderLookup(t *testing.T) {
+	generic := writeSchema(t, `
+oem: generic
+types:
+  - info_type: 0x80
+    fields: [{name: a, type: uint8}]
+`)
+	oem := writeSchema(t, `
+oem: example
+vin_prefixes: ["LSV"]
+platforms: ["platform_a"]
+types:
+  - info_type: 0x80
+    message_type: EXAMPLE_BMS
+    fields: [{name: b, type: uint8}]
+  - info_type: 0x09
+    version: "2025"
+    fields: [{name: c, type: uint8}]
+`)
+	d, err := LoadCustomSchemas(generic, oem)
+	if err != nil {
+		t.Fatalf("LoadCustomSchemas: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		version  ProtocolVersion
+		infoType byte
+		vin      string
+		platform string
+		message  string // 为空表示无匹配
+	}{
+		{"VIN 前缀优先于通用描述", Version2016, 0x80, "LSVAU2180N2183294", "", "EXAMPLE_BMS"},
+		{"平台账号匹配", Version2016, 0x80, "LFV00000000000000", "platform_a", "EXAMPLE_BMS"},
+		{"通用描述兜底", Version2016, 0x80, "LFV00000000000000", "platform_b", "CUSTOM_80"},
+		{"2025 版 0x09", Version2025, 0x09, "LSVAU2180N2183294", "", "CUSTOM_09"},
+		{"2016 版无 0x09 描述", Version2016, 0x09, "LSVAU2180N2183294", "", ""},
+		{"未描述的信息类型", Version2016, 0x81, "LSVAU2180N2183294", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, ok := d.Lookup(tt.version, tt.infoType, tt.vin, tt.platform)
+			if ok != (tt.message != "") {
+				t.Fatalf("Lookup() ok = %v, 期望 %v", ok, tt.message != "")
+			}
+			if ok && schema.Message() != tt.message {
+				t.Errorf("Message() = %s, 期望 %s", schema.Message(), tt.message)
+			}
+		})
+	}
+
+	var empty *CustomDecoder
+	if _, ok := empty.Lookup(Version2016, 0x80, "", ""); ok {
+		t.Error("nil 解码器不应匹配")
+	}
+}
+
+func TestLoadExampleSchema(t *testing.T) {
+	d, err := LoadCustomSchemas("../../../configs/custom_types/example_oem.yaml")
+	if err != nil {
+		t.Fatalf("示例描述加载失败: %v", err)
+	}
+	schema, ok := d.Lookup(Version2016, 0x80, "LSVAU2180N2183294", "")
+	if !ok {
+		t.Fatal("示例描述未匹配 0x80")
+	}
+	// soh 250*0.4, coolant_temp -125*0.1, 保留 1 字节, 1 个电池包: "P001", 0x0FA0*0.1 (小端)
+	data := []byte{0xFA, 0xFF, 0x83, 0x00, 0x01, 'P', '0', '0', '1', 0xA0, 0x0F}
+	got, err := schema.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	want := map[string]interface{}{
+		"soh":          100.0,
+		"coolant_temp": -12.5,
+		"pack_count":   int64(1),
+		"packs":        []map[string]interface{}{{"code": "P001", "voltage": 400.0}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() = %v, 期望 %v", got, want)
+	}
+}
+
+func TestSplitCustomBlock(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		block    []byte
+		consumed int
+		wantErr  bool
+	}{
+		{"完整数据块", []byte{0x00, 0x02, 0xAA, 0xBB, 0xCC}, []byte{0xAA, 0xBB}, 4, false},
+		{"空数据块", []byte{0x00, 0x00}, []byte{}, 2, false},
+		{"缺少长度字段", []byte{0x00}, nil, 0, true},
+		{"数据不足", []byte{0x00, 0x03, 0xAA}, nil, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, n, err := SplitCustomBlock(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SplitCustomBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (n != tt.consumed || !reflect.DeepEqual(block, tt.block)) {
+				t.Errorf("SplitCustomBlock() = %x, %d, 期望 %x, %d", block, n, tt.block, tt.consumed)
+			}
+		})
+	}
+}
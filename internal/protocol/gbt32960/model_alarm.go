@@ -2,13 +2,13 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
 )
 
 // AlarmData 报警数据 (类型 0x07 for 2016, 0x06 for 2025)
 type AlarmData struct {
 	MaxAlarmLevel byte     // 最高报警等级
 	AlarmMask     uint32   // 通用报警标志 (位掩码)
+	ActiveFlags   []string // 已置位的通用报警标志名称 (按协议版本展开, 见 AlarmFlags)
 	BatteryFaults byte     // 可充电储能装置故障总数 N1
 	BatteryCodes  []uint32 // 故障代码列表 (4字节/个)
 	MotorFaults   byte     // 驱动电机故障总数 N2
@@ -21,6 +21,22 @@ type AlarmData struct {
 	// 2025 Extended Fields
 	GeneralFaults byte     // 通用报警故障总数 N5
 	GeneralCodes  []uint16 // 通用报警故障等级列表
+	extended      bool     // 含通用报警 N5 (2025 版)
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *AlarmData) Length() int {
+	n := 5 +
+		1 + 4*int(d.BatteryFaults) +
+		1 + 4*int(d.MotorFaults) +
+		1 + 4*int(d.EngineFaults) +
+		1 + 4*int(d.OtherFaults)
+	if d.extended {
+		n += 1 + 2*int(d.GeneralFaults)
+	}
+	return n
 }
 
 // ParseAlarmData2016 解析2016版报警数据 (N1-N4)
@@ -34,81 +50,102 @@ func ParseAlarmData2025(data []byte) (*AlarmData, error) {
 }
 
 func parseAlarmDataCommon(data []byte, is2025 bool) (*AlarmData, error) {
+	if len(data) < 5 {
+		return nil, errShort("报警数据长度不足", 0, 5, len(data))
+	}
 
 	alarm := &AlarmData{
 		MaxAlarmLevel: data[0],
 		AlarmMask:     binary.BigEndian.Uint32(data[1:5]),
+		extended:      is2025,
+	}
+	alarm.Validity.byteOK("MaxAlarmLevel", data[0])
+	if alarm.Validity.dwordOK("AlarmMask", alarm.AlarmMask) {
+		version := Version2016
+		if is2025 {
+			version = Version2025
+		}
+		alarm.ActiveFlags = DecodeAlarmMask(version, alarm.AlarmMask)
 	}
 	offset := 5
 
 	// 1. Battery N1
 	if offset >= len(data) {
-		return nil, errors.New("报警数据不完整(N1)")
+		return nil, errShort("报警数据不完整(N1)", offset, 1, 0)
 	}
-	alarm.BatteryFaults = data[offset]
+	alarm.BatteryFaults = alarm.faultCount("BatteryFaults", data[offset])
 	offset++
 	codes, bytesRead := readCodes(data[offset:], int(alarm.BatteryFaults))
 	if codes == nil {
-		return nil, errors.New("报警数据(电池)解析溢出")
+		return nil, errShort("报警数据(电池)故障码不完整", offset, 4*int(alarm.BatteryFaults), len(data)-offset)
 	}
 	alarm.BatteryCodes = codes
 	offset += bytesRead
 
 	// 2. Motor N2
 	if offset >= len(data) {
-		return nil, errors.New("报警数据不完整(N2)")
+		return nil, errShort("报警数据不完整(N2)", offset, 1, 0)
 	}
-	alarm.MotorFaults = data[offset]
+	alarm.MotorFaults = alarm.faultCount("MotorFaults", data[offset])
 	offset++
 	codes, bytesRead = readCodes(data[offset:], int(alarm.MotorFaults))
 	if codes == nil {
-		return nil, errors.New("报警数据(电机)解析溢出")
+		return nil, errShort("报警数据(电机)故障码不完整", offset, 4*int(alarm.MotorFaults), len(data)-offset)
 	}
 	alarm.MotorCodes = codes
 	offset += bytesRead
 
 	// 3. Engine N3
 	if offset >= len(data) {
-		return nil, errors.New("报警数据不完整(N3)")
+		return nil, errShort("报警数据不完整(N3)", offset, 1, 0)
 	}
-	alarm.EngineFaults = data[offset]
+	alarm.EngineFaults = alarm.faultCount("EngineFaults", data[offset])
 	offset++
 	codes, bytesRead = readCodes(data[offset:], int(alarm.EngineFaults))
 	if codes == nil {
-		return nil, errors.New("报警数据(发动机)解析溢出")
+		return nil, errShort("报警数据(发动机)故障码不完整", offset, 4*int(alarm.EngineFaults), len(data)-offset)
 	}
 	alarm.EngineCodes = codes
 	offset += bytesRead
 
 	// 4. Other N4
 	if offset >= len(data) {
-		return nil, errors.New("报警数据不完整(N4)")
+		return nil, errShort("报警数据不完整(N4)", offset, 1, 0)
 	}
-	alarm.OtherFaults = data[offset]
+	alarm.OtherFaults = alarm.faultCount("OtherFaults", data[offset])
 	offset++
 	codes, bytesRead = readCodes(data[offset:], int(alarm.OtherFaults))
 	if codes == nil {
-		return nil, errors.New("报警数据(其他)解析溢出")
+		return nil, errShort("报警数据(其他)故障码不完整", offset, 4*int(alarm.OtherFaults), len(data)-offset)
 	}
 	alarm.OtherCodes = codes
 	offset += bytesRead
 
 	// 5. General N5 (2025 Only)
 	if is2025 {
-		if offset < len(data) {
-			alarm.GeneralFaults = data[offset]
-			offset++
-			genCodes, _ := readGeneralCodes(data[offset:], int(alarm.GeneralFaults))
-			if genCodes == nil {
-				return nil, errors.New("报警数据(通用)解析溢出")
-			}
-			alarm.GeneralCodes = genCodes
+		if offset >= len(data) {
+			return nil, errShort("报警数据不完整(N5)", offset, 1, 0)
+		}
+		alarm.GeneralFaults = alarm.faultCount("GeneralFaults", data[offset])
+		offset++
+		genCodes, _ := readGeneralCodes(data[offset:], int(alarm.GeneralFaults))
+		if genCodes == nil {
+			return nil, errShort("报警数据(通用)故障码不完整", offset, 2*int(alarm.GeneralFaults), len(data)-offset)
 		}
+		alarm.GeneralCodes = genCodes
 	}
 
 	return alarm, nil
 }
 
+// faultCount 读取故障总数，异常/无效 (0xFE/0xFF) 时记录并视为无故障代码
+func (a *AlarmData) faultCount(name string, raw byte) byte {
+	if a.Validity.byteOK(name, raw) {
+		return raw
+	}
+	return 0
+}
+
 func readCodes(data []byte, count int) ([]uint32, int) {
 	length := count * 4
 	if len(data) < length {
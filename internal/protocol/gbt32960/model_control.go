@@ -0,0 +1,165 @@
+package gbt32960
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// ControlCommand 车载终端控制命令 ID (命令单元 0x82)
+type ControlCommand byte
+
+const (
+	ControlRemoteUpgrade   ControlCommand = 0x01 // 远程升级
+	ControlShutdown        ControlCommand = 0x02 // 车载终端关机
+	ControlReset           ControlCommand = 0x03 // 车载终端复位
+	ControlFactoryReset    ControlCommand = 0x04 // 车载终端恢复出厂设置
+	ControlDisconnect      ControlCommand = 0x05 // 断开数据通信链路
+	ControlAlarm           ControlCommand = 0x06 // 车载终端报警/预警
+	ControlSamplingMonitor ControlCommand = 0x07 // 开启抽样监测链路
+)
+
+// controlParamSeparator 远程升级参数之间以半角分号分隔
+const controlParamSeparator = ';'
+
+// RemoteUpgradeParams 远程升级命令参数
+type RemoteUpgradeParams struct {
+	DialName        string // 拨号点名称
+	DialUser        string // 拨号用户名
+	DialPassword    string // 拨号密码
+	ServerIP        net.IP // 升级服务器地址 (IPv4)
+	ServerPort      uint16 // 升级服务器端口
+	ManufacturerID  string // 车载终端制造商 ID (4 字节)
+	HardwareVersion string // 硬件版本 (5 字节)
+	FirmwareVersion string // 固件版本 (5 字节)
+	URL             string // 升级 URL 地址
+	ConnectTimeout  uint16 // 连接到升级服务器时限 (min)
+}
+
+// TerminalAlarmParams 车载终端报警/预警命令参数
+type TerminalAlarmParams struct {
+	Level byte   // 报警等级
+	Info  string // 报警信息
+}
+
+// TerminalControlResult 车载终端控制应答 (命令单元 0x82)
+type TerminalControlResult struct {
+	CollectTime time.Time      // 控制时间 (终端回显)
+	Command     ControlCommand // 控制命令 ID (终端未回显时为 0)
+	Success     bool           // 应答标志为 0x01
+	Response    byte           // 应答标志
+}
+
+// BuildRemoteUpgrade 构建远程升级命令数据单元
+// 参数格式: 拨号点名称;拨号用户名;拨号密码;地址(6);端口(2);制造商ID(4);硬件版本(5);固件版本(5);升级URL;连接时限(2)
+func BuildRemoteUpgrade(now time.Time, p RemoteUpgradeParams) ([]byte, error) {
+	if p.URL == "" {
+		return nil, errors.New("远程升级 URL 不能为空")
+	}
+	if len(p.ManufacturerID) > 4 {
+		return nil, errors.New("制造商 ID 超过 4 字节")
+	}
+	if len(p.HardwareVersion) > versionLength || len(p.FirmwareVersion) > versionLength {
+		return nil, fmt.Errorf("版本号超过 %d 字节", versionLength)
+	}
+
+	// 地址: 6 字节, IPv4 时前 2 字节为 0
+	addr := make([]byte, 6)
+	if p.ServerIP != nil {
+		ip4 := p.ServerIP.To4()
+		if ip4 == nil {
+			return nil, fmt.Errorf("仅支持 IPv4 升级服务器地址: %s", p.ServerIP)
+		}
+		copy(addr[2:], ip4)
+	}
+
+	fields := [][]byte{
+		[]byte(p.DialName),
+		[]byte(p.DialUser),
+		[]byte(p.DialPassword),
+		addr,
+		{byte(p.ServerPort >> 8), byte(p.ServerPort)},
+		fixedString(p.ManufacturerID, 4),
+		fixedString(p.HardwareVersion, versionLength),
+		fixedString(p.FirmwareVersion, versionLength),
+		[]byte(p.URL),
+		{byte(p.ConnectTimeout >> 8), byte(p.ConnectTimeout)},
+	}
+
+	var params []byte
+	for i, f := range fields {
+		if i > 0 {
+			params = append(params, controlParamSeparator)
+		}
+		params = append(params, f...)
+	}
+	return buildControl(now, ControlRemoteUpgrade, params), nil
+}
+
+// BuildTerminalShutdown 构建车载终端关机命令数据单元
+func BuildTerminalShutdown(now time.Time) []byte {
+	return buildControl(now, ControlShutdown, nil)
+}
+
+// BuildTerminalReset 构建车载终端复位命令数据单元
+func BuildTerminalReset(now time.Time) []byte {
+	return buildControl(now, ControlReset, nil)
+}
+
+// BuildFactoryReset 构建车载终端恢复出厂设置命令数据单元
+func BuildFactoryReset(now time.Time) []byte {
+	return buildControl(now, ControlFactoryReset, nil)
+}
+
+// BuildDisconnectLink 构建断开数据通信链路命令数据单元
+func BuildDisconnectLink(now time.Time) []byte {
+	return buildControl(now, ControlDisconnect, nil)
+}
+
+// BuildTerminalAlarm 构建车载终端报警/预警命令数据单元
+// 参数格式: [报警等级 1][报警信息 N]
+func BuildTerminalAlarm(now time.Time, p TerminalAlarmParams) []byte {
+	params := append([]byte{p.Level}, p.Info...)
+	return buildControl(now, ControlAlarm, params)
+}
+
+// buildControl 构建车载终端控制命令数据单元
+// 格式: [控制时间 6][命令 ID 1][命令参数 N]
+func buildControl(now time.Time, cmd ControlCommand, params []byte) []byte {
+	data := make([]byte, 0, 7+len(params))
+	data = append(data, EncodeTime(now)...)
+	data = append(data, byte(cmd))
+	data = append(data, params...)
+	return data
+}
+
+// ParseTerminalControlResponse 解析车载终端控制应答
+// 格式: [控制时间 6][命令 ID 1 (可选)]
+func ParseTerminalControlResponse(response byte, data []byte) (*TerminalControlResult, error) {
+	if len(data) < 6 {
+		return nil, errors.New("终端控制应答长度不足")
+	}
+
+	t, err := ParseCollectTime(data[0:6])
+	if err != nil {
+		return nil, err
+	}
+
+	result := &TerminalControlResult{
+		CollectTime: t,
+		Success:     response == 0x01,
+		Response:    response,
+	}
+	if len(data) >= 7 {
+		result.Command = ControlCommand(data[6])
+	}
+	return result, nil
+}
+
+// fixedString 将字符串写入定长字节数组，不足补 0
+func fixedString(s string, n int) []byte {
+	b := make([]byte, n)
+	copy(b, s)
+	return b
+}
@@ -0,0 +1,309 @@
+package gbt32960
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// 数据模型编码 (与 Parse* 对称)
+// Marshal 输出信息类型标志之后的数据块，物理值按协议精度与偏移量换算 (四舍五入并限制在有效范围内)，
+// Validity 中记录为异常/无效的字段写出 0xFE / 0xFF 标记; 个数字段取自对应列表的长度，Raw 不参与编码
+
+// RealTimeUnit 实时信息中的一个信息单元
+type RealTimeUnit struct {
+	InfoType byte   // 信息类型标志
+	Body     []byte // 数据块 (通常为模型 Marshal 的结果)
+}
+
+// EncodeRealTimeData 编码实时信息上报 / 补发信息上报的数据单元: [采集时间 6][信息类型 1][数据]...
+func EncodeRealTimeData(collectTime time.Time, units ...RealTimeUnit) []byte {
+	buf := EncodeTime(collectTime)
+	for _, u := range units {
+		buf = append(buf, u.InfoType)
+		buf = append(buf, u.Body...)
+	}
+	return buf
+}
+
+// toWord 物理值换算为 WORD 原始值: (v + offset) / precision, 限制在 0~0xFFFD
+func toWord(v, precision, offset float64) uint16 {
+	return uint16(scaleRaw(v, precision, offset, 0xFFFD))
+}
+
+// toDword 物理值换算为 DWORD 原始值, 限制在 0~0xFFFFFFFD
+func toDword(v, precision, offset float64) uint32 {
+	return uint32(scaleRaw(v, precision, offset, 0xFFFFFFFD))
+}
+
+func scaleRaw(v, precision, offset, max float64) float64 {
+	raw := math.Round((v + offset) / precision)
+	if raw < 0 || math.IsNaN(raw) {
+		return 0
+	}
+	if raw > max {
+		return max
+	}
+	return raw
+}
+
+// tempByte 摄氏度编码为偏移 40℃ 的原始值, 限制在 0~250 (-40℃~210℃)
+func tempByte(t int16) byte {
+	raw := int(t) + 40
+	if raw < 0 {
+		return 0
+	}
+	if raw > 250 {
+		return 250
+	}
+	return byte(raw)
+}
+
+// appendWords 编码 WORD 数组 (如单体电压)，异常/无效的元素 (name[i]) 写出标记
+func appendWords(buf []byte, s FieldStatus, name string, values []float32, precision float64) []byte {
+	for i, v := range values {
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord(indexed(name, i), toWord(float64(v), precision, 0)))
+	}
+	return buf
+}
+
+// appendTemps 编码探针温度数组，异常/无效的探针 (name[i]) 写出标记
+func appendTemps(buf []byte, s FieldStatus, name string, temps []int16) []byte {
+	for i, t := range temps {
+		buf = append(buf, s.markByte(indexed(name, i), tempByte(t)))
+	}
+	return buf
+}
+
+// Marshal 编码整车数据 (20 字节)
+func (d *VehicleData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, d.Length())
+	buf = append(buf, s.markByte("Status", d.Status), s.markByte("ChargeStatus", d.ChargeStatus), s.markByte("RunMode", d.RunMode))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Speed", toWord(float64(d.Speed), 0.1, 0)))
+	buf = binary.BigEndian.AppendUint32(buf, s.markDword("TotalMileage", toDword(d.TotalMileage, 0.1, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(d.Voltage), 0.1, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(d.Current), 0.1, 1000)))
+	buf = append(buf, s.markByte("SOC", d.SOC), s.markByte("DCStatus", d.DCStatus), d.Gear)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("InsulationRes", d.InsulationRes))
+	return append(buf, s.markByte("AccelPedal", d.AccelPedal), s.markByte("BrakePedal", d.BrakePedal))
+}
+
+// Marshal 编码驱动电机数据，电机个数取自 MotorList
+func (d *MotorData) Marshal() []byte {
+	buf := make([]byte, 0, 1+len(d.MotorList)*12)
+	buf = append(buf, byte(len(d.MotorList)))
+	for i := range d.MotorList {
+		buf = d.MotorList[i].appendTo(buf)
+	}
+	return buf
+}
+
+// appendTo 编码单个驱动电机 (12 字节)
+func (u *MotorUnit) appendTo(buf []byte) []byte {
+	s := u.Validity
+	buf = append(buf, u.Seq, s.markByte("Status", u.Status), s.markByte("CtrlTemp", tempByte(u.CtrlTemp)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Speed", toWord(float64(u.Speed), 1, 20000)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Torque", toWord(float64(u.Torque), 0.1, 2000)))
+	buf = append(buf, s.markByte("Temp", tempByte(u.Temp)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(u.Voltage), 0.1, 0)))
+	return binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(u.Current), 0.1, 1000)))
+}
+
+// Marshal 编码燃料电池数据，探针总数取自 ProbeTemps
+func (d *FuelCellData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, 8+len(d.ProbeTemps)+fuelCellTailLength)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(d.Voltage), 0.1, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(d.Current), 0.1, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("FuelConsumeRate", toWord(float64(d.FuelConsumeRate), 0.01, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(d.ProbeTemps)))
+	buf = appendTemps(buf, s, "ProbeTemps", d.ProbeTemps)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MaxTemp", toWord(float64(d.MaxTemp), 0.1, 40)))
+	buf = append(buf, s.markByte("MaxTempProbe", d.MaxTempProbe))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MaxH2Conc", d.MaxH2Conc))
+	buf = append(buf, s.markByte("MaxH2ConcSensor", d.MaxH2ConcSensor))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MaxH2Pressure", toWord(float64(d.MaxH2Pressure), 0.1, 0)))
+	return append(buf, s.markByte("MaxH2PresSensor", d.MaxH2PresSensor), s.markByte("DCDCStatus", d.DCDCStatus))
+}
+
+// Marshal 编码发动机数据 (5 字节)
+func (d *EngineData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, engineDataLength)
+	buf = append(buf, s.markByte("Status", d.Status))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("Speed", d.Speed))
+	return binary.BigEndian.AppendUint16(buf, s.markWord("FuelRate", toWord(float64(d.FuelRate), 0.01, 0)))
+}
+
+// Marshal 编码车辆位置数据 (9 字节)
+// 定位状态的有效位与南纬/西经位按 Valid 与经纬度符号重新设置
+func (d *LocationData) Marshal() []byte {
+	s := d.Validity
+	state := d.State &^ (LocationInvalid | LocationSouth | LocationWest)
+	if !d.Valid {
+		state |= LocationInvalid
+	}
+	if d.Latitude < 0 {
+		state |= LocationSouth
+	}
+	if d.Longitude < 0 {
+		state |= LocationWest
+	}
+	buf := make([]byte, 0, d.Length())
+	buf = append(buf, state)
+	buf = binary.BigEndian.AppendUint32(buf, s.markDword("Longitude", toDword(math.Abs(d.Longitude), 1e-6, 0)))
+	return binary.BigEndian.AppendUint32(buf, s.markDword("Latitude", toDword(math.Abs(d.Latitude), 1e-6, 0)))
+}
+
+// Marshal 编码极值数据 (14 字节)
+func (d *ExtremeData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, d.Length())
+	buf = append(buf, s.markByte("MaxVoltageSubSysID", d.MaxVoltageSubSysID), s.markByte("MaxVoltageProbeID", d.MaxVoltageProbeID))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MaxVoltage", toWord(float64(d.MaxVoltage), 0.001, 0)))
+	buf = append(buf, s.markByte("MinVoltageSubSysID", d.MinVoltageSubSysID), s.markByte("MinVoltageProbeID", d.MinVoltageProbeID))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MinVoltage", toWord(float64(d.MinVoltage), 0.001, 0)))
+	buf = append(buf, s.markByte("MaxTempSubSysID", d.MaxTempSubSysID), s.markByte("MaxTempProbeID", d.MaxTempProbeID))
+	buf = append(buf, s.markByte("MaxTemp", tempByte(d.MaxTemp)))
+	buf = append(buf, s.markByte("MinTempSubSysID", d.MinTempSubSysID), s.markByte("MinTempProbeID", d.MinTempProbeID))
+	return append(buf, s.markByte("MinTemp", tempByte(d.MinTemp)))
+}
+
+// NewAlarmData 创建指定协议版本的报警数据 (2025 版编码时含通用报警 N5)
+func NewAlarmData(version ProtocolVersion) *AlarmData {
+	return &AlarmData{extended: version == Version2025}
+}
+
+// Marshal 编码报警数据，故障总数取自各故障代码列表
+// 协议版本取自解析结果或 NewAlarmData, 2025 版追加通用报警 N5
+func (d *AlarmData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, 9+4*(len(d.BatteryCodes)+len(d.MotorCodes)+len(d.EngineCodes)+len(d.OtherCodes)))
+	buf = append(buf, s.markByte("MaxAlarmLevel", d.MaxAlarmLevel))
+	buf = binary.BigEndian.AppendUint32(buf, s.markDword("AlarmMask", d.AlarmMask))
+	for _, f := range []struct {
+		name  string
+		codes []uint32
+	}{
+		{"BatteryFaults", d.BatteryCodes},
+		{"MotorFaults", d.MotorCodes},
+		{"EngineFaults", d.EngineCodes},
+		{"OtherFaults", d.OtherCodes},
+	} {
+		buf = append(buf, s.markByte(f.name, byte(len(f.codes))))
+		for _, c := range f.codes {
+			buf = binary.BigEndian.AppendUint32(buf, c)
+		}
+	}
+	if d.extended {
+		buf = append(buf, s.markByte("GeneralFaults", byte(len(d.GeneralCodes))))
+		for _, c := range d.GeneralCodes {
+			buf = binary.BigEndian.AppendUint16(buf, c)
+		}
+	}
+	return buf
+}
+
+// Marshal 编码 2016 版可充电储能装置电压数据 (0x08)
+// 子系统个数与本帧单体个数取自列表长度，单体电池总数与起始序号按字段写出
+func (d *StorageVoltageData2016) Marshal() []byte {
+	buf := []byte{byte(len(d.Subsystems))}
+	for i := range d.Subsystems {
+		sub := &d.Subsystems[i]
+		s := sub.Validity
+		buf = append(buf, sub.SystemNo)
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(sub.Voltage), 0.1, 0)))
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(sub.Current), 0.1, 1000)))
+		buf = binary.BigEndian.AppendUint16(buf, sub.SingleCellCount)
+		buf = binary.BigEndian.AppendUint16(buf, sub.StartFrameSeq)
+		buf = append(buf, byte(len(sub.CellVoltages)))
+		buf = appendWords(buf, s, "CellVoltages", sub.CellVoltages, 0.001)
+	}
+	return buf
+}
+
+// Marshal 编码 2025 版动力蓄电池最小并联单元电压数据 (0x07)
+func (d *BatteryVoltageData) Marshal() []byte {
+	buf := []byte{byte(len(d.PackVoltages))}
+	for i := range d.PackVoltages {
+		p := &d.PackVoltages[i]
+		s := p.Validity
+		buf = append(buf, p.PackSeq)
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(p.Voltage), 0.1, 0)))
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(p.Current), 0.1, 3000)))
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(p.FrameCellVoltages)))
+		buf = appendWords(buf, s, "FrameCellVoltages", p.FrameCellVoltages, 0.01)
+	}
+	return buf
+}
+
+// Marshal 编码 2016 版可充电储能装置温度数据 (0x09)
+func (d *StorageTempData2016) Marshal() []byte {
+	buf := []byte{byte(len(d.Subsystems))}
+	for _, sub := range d.Subsystems {
+		buf = append(buf, sub.SystemNo)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(sub.Temperatures)))
+		buf = appendTemps(buf, sub.Validity, "Temperatures", sub.Temperatures)
+	}
+	return buf
+}
+
+// Marshal 编码 2025 版动力蓄电池温度数据 (0x08)
+func (d *BatteryTempData) Marshal() []byte {
+	buf := []byte{byte(len(d.PackTemps))}
+	for _, p := range d.PackTemps {
+		buf = append(buf, p.PackSeq)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(p.ProbeTemps)))
+		buf = appendTemps(buf, p.Validity, "ProbeTemps", p.ProbeTemps)
+	}
+	return buf
+}
+
+// Marshal 编码燃料电池电堆数据 (0x30)
+func (d *FuelCellStackData) Marshal() []byte {
+	buf := []byte{byte(len(d.Stacks))}
+	for i := range d.Stacks {
+		st := &d.Stacks[i]
+		s := st.Validity
+		buf = append(buf, st.StackSeq)
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Voltage", toWord(float64(st.Voltage), 0.1, 0)))
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("Current", toWord(float64(st.Current), 0.1, 0)))
+		buf = binary.BigEndian.AppendUint16(buf, s.markWord("AirInPressure", toWord(float64(st.AirInPressure), 0.1, 100)))
+		buf = append(buf, s.markByte("AirInTemp", tempByte(st.AirInTemp)))
+		buf = binary.BigEndian.AppendUint16(buf, uint16(len(st.ProbeTemps)))
+		buf = appendTemps(buf, s, "ProbeTemps", st.ProbeTemps)
+	}
+	return buf
+}
+
+// Marshal 编码超级电容器数据 (0x31)，单体个数与探针个数取自列表长度
+func (d *SuperCapData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, 9+2*len(d.SingleCellVolts)+len(d.ProbeTemps))
+	buf = append(buf, d.SystemNo)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("TotalVoltage", toWord(float64(d.TotalVoltage), 0.1, 0)))
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("TotalCurrent", toWord(float64(d.TotalCurrent), 0.1, 3000)))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(d.SingleCellVolts)))
+	buf = appendWords(buf, s, "SingleCellVolts", d.SingleCellVolts, 0.01)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(d.ProbeTemps)))
+	return appendTemps(buf, s, "ProbeTemps", d.ProbeTemps)
+}
+
+// Marshal 编码超级电容器极值数据 (0x32, 18 字节)
+func (d *SuperCapExtremeData) Marshal() []byte {
+	s := d.Validity
+	buf := make([]byte, 0, d.Length())
+	buf = append(buf, d.MaxVoltSystemNo)
+	buf = binary.BigEndian.AppendUint16(buf, d.MaxVoltCellCode)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MaxVoltValue", toWord(float64(d.MaxVoltValue), 0.001, 0)))
+	buf = append(buf, d.MinVoltSystemNo)
+	buf = binary.BigEndian.AppendUint16(buf, d.MinVoltCellCode)
+	buf = binary.BigEndian.AppendUint16(buf, s.markWord("MinVoltValue", toWord(float64(d.MinVoltValue), 0.001, 0)))
+	buf = append(buf, d.MaxTempSystemNo)
+	buf = binary.BigEndian.AppendUint16(buf, d.MaxTempProbeCode)
+	buf = append(buf, s.markByte("MaxTempValue", tempByte(d.MaxTempValue)))
+	buf = append(buf, d.MinTempSystemNo)
+	buf = binary.BigEndian.AppendUint16(buf, d.MinTempProbeCode)
+	return append(buf, s.markByte("MinTempValue", tempByte(d.MinTempValue)))
+}
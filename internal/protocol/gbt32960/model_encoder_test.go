@@ -0,0 +1,234 @@
+package gbt32960
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// roundTripModel 可编码且可计算长度的数据模型
+type roundTripModel interface {
+	Marshal() []byte
+	Length() int
+}
+
+func TestMarshalParseRoundTrip(t *testing.T) {
+	alarm2016 := NewAlarmData(Version2016)
+	alarm2016.MaxAlarmLevel = 2
+	alarm2016.AlarmMask = 0x05
+	alarm2016.ActiveFlags = DecodeAlarmMask(Version2016, 0x05)
+	alarm2016.BatteryFaults, alarm2016.BatteryCodes = 2, []uint32{0x01020304, 0x0A0B0C0D}
+	alarm2016.OtherFaults, alarm2016.OtherCodes = 1, []uint32{9}
+
+	alarm2025 := NewAlarmData(Version2025)
+	alarm2025.MaxAlarmLevel = 1
+	alarm2025.AlarmMask = 0x01
+	alarm2025.ActiveFlags = DecodeAlarmMask(Version2025, 0x01)
+	alarm2025.MotorFaults, alarm2025.MotorCodes = 1, []uint32{7}
+	alarm2025.GeneralFaults, alarm2025.GeneralCodes = 2, []uint16{1, 3}
+
+	alarmInvalid := NewAlarmData(Version2016)
+	alarmInvalid.Validity = FieldStatus{"MaxAlarmLevel": FieldAbnormal, "AlarmMask": FieldInvalid, "EngineFaults": FieldInvalid}
+	alarmInvalid.MaxAlarmLevel = 0xFE // 状态类字段保留原始标记值
+	alarmInvalid.AlarmMask = 0xFFFFFFFF
+
+	tests := []struct {
+		name  string
+		model roundTripModel
+		parse func([]byte) (roundTripModel, error)
+	}{
+		{"vehicle", &VehicleData{
+			Status: 0x01, ChargeStatus: 0x03, RunMode: 0x01, Speed: 60.1, TotalMileage: 12345.6,
+			Voltage: 380.2, Current: -25.5, SOC: 80, DCStatus: 0x01, Gear: 0x0E, InsulationRes: 5000, AccelPedal: 20,
+		}, func(b []byte) (roundTripModel, error) { return ParseVehicleData(b) }},
+		{"vehicle/validity", &VehicleData{
+			Status: 0x01, SOC: 0xFF, TotalMileage: 100,
+			Validity: FieldStatus{"Speed": FieldAbnormal, "Current": FieldInvalid, "SOC": FieldInvalid},
+		}, func(b []byte) (roundTripModel, error) { return ParseVehicleData(b) }},
+		{"motor", &MotorData{Count: 2, MotorList: []MotorUnit{
+			{Seq: 1, Status: 0x01, CtrlTemp: -5, Speed: -1500, Torque: -120.5, Temp: 60, Voltage: 380, Current: -50.3},
+			{Seq: 2, Status: 0x02, CtrlTemp: 40, Speed: 3000, Torque: 80, Voltage: 381, Current: 10,
+				Validity: FieldStatus{"Temp": FieldInvalid}},
+		}}, func(b []byte) (roundTripModel, error) { return ParseMotorData(b) }},
+		{"fuel cell", &FuelCellData{
+			Voltage: 300, Current: 12.3, FuelConsumeRate: 1.23, TempProbeCount: 3, ProbeTemps: []int16{20, -10, 0},
+			MaxTemp: 55.5, MaxTempProbe: 1, MaxH2Conc: 100, MaxH2ConcSensor: 2, MaxH2Pressure: 35.2, MaxH2PresSensor: 3, DCDCStatus: 0x01,
+			Validity: FieldStatus{"ProbeTemps[2]": FieldInvalid},
+		}, func(b []byte) (roundTripModel, error) { return ParseFuelCellData(b) }},
+		{"engine", &EngineData{Status: 0x01, Speed: 800, FuelRate: 7.55},
+			func(b []byte) (roundTripModel, error) { return ParseEngineData(b) }},
+		{"location", &LocationData{State: LocationSouth | LocationWest, Valid: true, Longitude: -116.397128, Latitude: -39.916527},
+			func(b []byte) (roundTripModel, error) { return ParseLocationData(b) }},
+		{"location/invalid", &LocationData{State: LocationInvalid, Longitude: 116.397128, Validity: FieldStatus{"Latitude": FieldInvalid}},
+			func(b []byte) (roundTripModel, error) { return ParseLocationData(b) }},
+		{"extreme", &ExtremeData{
+			MaxVoltageSubSysID: 1, MaxVoltageProbeID: 2, MaxVoltage: 3.666, MinVoltageSubSysID: 1, MinVoltageProbeID: 4, MinVoltage: 3.5,
+			MaxTempSubSysID: 1, MaxTempProbeID: 3, MaxTemp: 30, MinTempSubSysID: 1, MinTempProbeID: 1, MinTemp: -20,
+		}, func(b []byte) (roundTripModel, error) { return ParseExtremeData(b) }},
+		{"alarm/2016", alarm2016, func(b []byte) (roundTripModel, error) { return ParseAlarmData2016(b) }},
+		{"alarm/2025", alarm2025, func(b []byte) (roundTripModel, error) { return ParseAlarmData2025(b) }},
+		{"alarm/validity", alarmInvalid, func(b []byte) (roundTripModel, error) { return ParseAlarmData2016(b) }},
+		{"storage voltage/2016", &StorageVoltageData2016{SubsystemCount: 1, Subsystems: []StorageSubsystemInfo{{
+			SystemNo: 1, Voltage: 380, Current: -25.5, SingleCellCount: 96, StartFrameSeq: 1, FrameCellCount: 3,
+			CellVoltages: []float32{3.651, 0, 3.7}, Validity: FieldStatus{"CellVoltages[1]": FieldInvalid},
+		}}}, func(b []byte) (roundTripModel, error) { return ParseStorageVoltageData2016(b) }},
+		{"storage temp/2016", &StorageTempData2016{SubsystemCount: 1, Subsystems: []StorageTempSubsystem{{
+			SystemNo: 1, ProbeCount: 3, Temperatures: []int16{25, -40, 0}, Validity: FieldStatus{"Temperatures[2]": FieldAbnormal},
+		}}}, func(b []byte) (roundTripModel, error) { return ParseStorageTempData2016(b) }},
+		{"battery voltage/2025", &BatteryVoltageData{BatteryPackCount: 1, PackVoltages: []BatteryPackInfo{{
+			PackSeq: 1, Voltage: 380, Current: -2500, SingleCellCount: 2, FrameCellVoltages: []float32{3.65, 3.66},
+		}}}, func(b []byte) (roundTripModel, error) { return ParseBatteryVoltageData2025(b) }},
+		{"battery temp/2025", &BatteryTempData{BatteryPackCount: 2, PackTemps: []BatteryPackTemp{
+			{PackSeq: 1, ProbeCount: 2, ProbeTemps: []int16{1, 2}},
+			{PackSeq: 2, ProbeCount: 1, ProbeTemps: []int16{0}, Validity: FieldStatus{"ProbeTemps[0]": FieldInvalid}},
+		}}, func(b []byte) (roundTripModel, error) { return ParseBatteryTempData2025(b) }},
+		{"fuel cell stack", &FuelCellStackData{StackCount: 1, Stacks: []FuelCellStackInfo{{
+			StackSeq: 1, Voltage: 200, Current: 100, AirInPressure: -50.5, AirInTemp: 30, ProbeCount: 2, ProbeTemps: []int16{40, 41},
+		}}}, func(b []byte) (roundTripModel, error) { return ParseFuelCellStackData(b) }},
+		{"super cap", &SuperCapData{
+			SystemNo: 1, TotalVoltage: 48, TotalCurrent: -10, SingleCellCount: 2, SingleCellVolts: []float32{2.7, 2.71},
+			ProbeCount: 1, ProbeTemps: []int16{30},
+		}, func(b []byte) (roundTripModel, error) { return ParseSuperCapData(b) }},
+		{"super cap extreme", &SuperCapExtremeData{
+			MaxVoltSystemNo: 1, MaxVoltCellCode: 2, MaxVoltValue: 2.71, MinVoltSystemNo: 1, MinVoltCellCode: 5, MinVoltValue: 2.7,
+			MaxTempSystemNo: 1, MaxTempProbeCode: 1, MaxTempValue: 30, MinTempSystemNo: 1, MinTempProbeCode: 2, MinTempValue: 20,
+		}, func(b []byte) (roundTripModel, error) { return ParseSuperCapExtremeData(b) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.model.Marshal()
+			if len(data) != tt.model.Length() {
+				t.Fatalf("编码长度 = %d, Length() = %d", len(data), tt.model.Length())
+			}
+			got, err := tt.parse(data)
+			if err != nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			assertModelEqual(t, "", reflect.ValueOf(got), reflect.ValueOf(tt.model))
+			if again := got.Marshal(); !bytes.Equal(again, data) {
+				t.Errorf("重新编码 = %x, 期望 %x", again, data)
+			}
+		})
+	}
+}
+
+func TestMarshalValidityMarkers(t *testing.T) {
+	v := &VehicleData{Validity: FieldStatus{"Speed": FieldAbnormal, "TotalMileage": FieldInvalid, "Voltage": FieldInvalid}}
+	data := v.Marshal()
+	if !bytes.Equal(data[3:5], []byte{0xFF, 0xFE}) {
+		t.Errorf("异常车速 = %x, 期望 fffe", data[3:5])
+	}
+	if !bytes.Equal(data[5:9], []byte{0xFF, 0xFF, 0xFF, 0xFF}) {
+		t.Errorf("无效里程 = %x, 期望 ffffffff", data[5:9])
+	}
+	if !bytes.Equal(data[9:11], []byte{0xFF, 0xFF}) {
+		t.Errorf("无效电压 = %x, 期望 ffff", data[9:11])
+	}
+
+	m := &MotorData{MotorList: []MotorUnit{{Seq: 1, Validity: FieldStatus{"CtrlTemp": FieldAbnormal}}}}
+	if got := m.Marshal()[3]; got != 0xFE {
+		t.Errorf("异常控制器温度 = %#x, 期望 0xfe", got)
+	}
+
+	s := &StorageVoltageData2016{Subsystems: []StorageSubsystemInfo{{
+		CellVoltages: []float32{3.6, 0}, Validity: FieldStatus{"CellVoltages[1]": FieldInvalid},
+	}}}
+	if got := s.Marshal()[13:15]; !bytes.Equal(got, []byte{0xFF, 0xFF}) {
+		t.Errorf("无效单体电压 = %x, 期望 ffff", got)
+	}
+}
+
+func TestMarshalClampsOutOfRange(t *testing.T) {
+	v := &VehicleData{Speed: -1, Current: 1e6}
+	data := v.Marshal()
+	if !bytes.Equal(data[3:5], []byte{0x00, 0x00}) {
+		t.Errorf("负车速 = %x, 期望 0000", data[3:5])
+	}
+	if !bytes.Equal(data[11:13], []byte{0xFF, 0xFD}) {
+		t.Errorf("超限电流 = %x, 期望 fffd (不得写成异常/无效标记)", data[11:13])
+	}
+	x := &ExtremeData{MaxTemp: 300, MinTemp: -100}
+	data = x.Marshal()
+	if data[10] != 250 || data[13] != 0 {
+		t.Errorf("超限温度 = %d / %d, 期望 250 / 0", data[10], data[13])
+	}
+}
+
+func TestEncodeRealTimeData(t *testing.T) {
+	collect := time.Date(2026, 1, 2, 3, 4, 5, 0, TimeZone())
+	vehicle := (&VehicleData{Status: 0x01, SOC: 80}).Marshal()
+	location := (&LocationData{Valid: true, Longitude: 116.397128, Latitude: 39.916527}).Marshal()
+
+	data := EncodeRealTimeData(collect,
+		RealTimeUnit{InfoType: byte(DataTypeVehicle), Body: vehicle},
+		RealTimeUnit{InfoType: byte(DataTypeLocation), Body: location},
+	)
+
+	if want := []byte{26, 1, 2, 3, 4, 5}; !bytes.Equal(data[:6], want) {
+		t.Errorf("采集时间 = %v, 期望 %v", data[:6], want)
+	}
+	registry := NewStandardRegistry()
+	rest := data[6:]
+	var types []string
+	for len(rest) > 0 {
+		unit, ok := registry.Lookup(Version2016, rest[0])
+		if !ok {
+			t.Fatalf("未知信息类型 %#x", rest[0])
+		}
+		_, n, err := unit.Decode(rest[1:])
+		if err != nil {
+			t.Fatalf("%s: %v", unit.MsgType, err)
+		}
+		types = append(types, unit.MsgType)
+		rest = rest[1+n:]
+	}
+	if want := []string{"VEHICLE", "LOCATION"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("信息类型 = %v, 期望 %v", types, want)
+	}
+}
+
+// assertModelEqual 逐字段比较解析结果与原模型: 忽略 Raw 与未导出字段，浮点数按精度容差比较，空切片与 nil 等同
+func assertModelEqual(t *testing.T, path string, got, want reflect.Value) {
+	t.Helper()
+	switch want.Kind() {
+	case reflect.Ptr:
+		if got.IsNil() || want.IsNil() {
+			if got.IsNil() != want.IsNil() {
+				t.Errorf("%s = %v, 期望 %v", path, got.Interface(), want.Interface())
+			}
+			return
+		}
+		assertModelEqual(t, path, got.Elem(), want.Elem())
+	case reflect.Struct:
+		for i := 0; i < want.NumField(); i++ {
+			f := want.Type().Field(i)
+			if !f.IsExported() || f.Name == "Raw" {
+				continue
+			}
+			assertModelEqual(t, path+"."+f.Name, got.Field(i), want.Field(i))
+		}
+	case reflect.Slice:
+		if got.Len() != want.Len() {
+			t.Errorf("%s: 长度 = %d, 期望 %d", path, got.Len(), want.Len())
+			return
+		}
+		for i := 0; i < want.Len(); i++ {
+			assertModelEqual(t, path, got.Index(i), want.Index(i))
+		}
+	case reflect.Map:
+		if got.Len() != want.Len() || (want.Len() > 0 && !reflect.DeepEqual(got.Interface(), want.Interface())) {
+			t.Errorf("%s = %v, 期望 %v", path, got.Interface(), want.Interface())
+		}
+	case reflect.Float32, reflect.Float64:
+		if math.Abs(got.Float()-want.Float()) > 1e-4 {
+			t.Errorf("%s = %v, 期望 %v", path, got.Float(), want.Float())
+		}
+	default:
+		if !reflect.DeepEqual(got.Interface(), want.Interface()) {
+			t.Errorf("%s = %v, 期望 %v", path, got.Interface(), want.Interface())
+		}
+	}
+}
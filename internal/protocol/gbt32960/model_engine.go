@@ -4,26 +4,39 @@ import (
 	"encoding/binary"
 )
 
+// engineDataLength 发动机数据长度: 状态(1) + 转速(2) + 消耗率(2)
+const engineDataLength = 5
+
 // EngineData 发动机数据 (类型 0x04)
 type EngineData struct {
 	Status   byte    // 发动机状态 (0x01:启动, 0x02:关闭)
 	Speed    uint16  // 曲轴转速 (r/min)
 	FuelRate float32 // 燃料消耗率 (L/100km), 精度0.01
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *EngineData) Length() int {
+	return engineDataLength
 }
 
 // ParseEngineData 解析发动机数据
 func ParseEngineData(data []byte) (*EngineData, error) {
-	//if len(data) < 5 {
-	//	return nil, errors.New("发动机数据长度不足")
-	//}
+	if len(data) < engineDataLength {
+		return nil, errShort("发动机数据长度不足", 0, engineDataLength, len(data))
+	}
 
-	// 状态(1) + 转速(2) + 消耗率(2) = 5
 	speed := binary.BigEndian.Uint16(data[1:3])
 	rate := binary.BigEndian.Uint16(data[3:5])
 
-	return &EngineData{
-		Status:   data[0],
-		Speed:    speed,
-		FuelRate: float32(rate) * 0.01,
-	}, nil
+	e := &EngineData{Status: data[0]}
+	e.Validity.byteOK("Status", data[0])
+	if e.Validity.wordOK("Speed", speed) {
+		e.Speed = speed
+	}
+	if e.Validity.wordOK("FuelRate", rate) {
+		e.FuelRate = float32(rate) * 0.01
+	}
+	return e, nil
 }
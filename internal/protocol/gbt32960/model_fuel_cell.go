@@ -2,24 +2,42 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
 )
 
 // FuelCellData 燃料电池数据 (类型 0x03)
 type FuelCellData struct {
-	Voltage         float32 // 燃料电池电压 (V), 精度0.1
-	Current         float32 // 燃料电池电流 (A), 精度0.1
-	FuelConsumeRate float32 // 燃料消耗率 (kg/100km), 精度0.01
-	TempProbeCount  uint16  // 燃料电池温度探针总数
-	ProbeTemps      []byte  // 探针温度值 (℃), 偏移40℃
-	MaxTemp         float32 // 氢系统中最高温度 (℃), 偏移40, 精度0.1 (标准2016版由 2+2+2+2+N + 2+1+1+1 = 6+N+6? 2025版可能有变化，此处按常规结构实现，需对照最新2025pdf)
+	Voltage         float32  // 燃料电池电压 (V), 精度0.1
+	Current         float32  // 燃料电池电流 (A), 精度0.1
+	FuelConsumeRate float32  // 燃料消耗率 (kg/100km), 精度0.01
+	TempProbeCount  uint16   // 燃料电池温度探针总数
+	ProbeTemps      []int16  // 探针温度值 (℃), 偏移40℃
+	MaxTemp         float32  // 氢系统中最高温度 (℃), 精度0.1, 偏移40℃
+	MaxTempProbe    byte     // 氢系统中最高温度探针代号
+	MaxH2Conc       uint16   // 氢气最高浓度 (mg/kg)
+	MaxH2ConcSensor byte     // 氢气最高浓度传感器代号
+	MaxH2Pressure   float32  // 氢气最高压力 (MPa), 精度0.1
+	MaxH2PresSensor byte     // 氢气最高压力传感器代号
+	DCDCStatus      byte     // 高压 DC/DC 状态 (0x01:工作, 0x02:断开)
+	Raw             RawBytes // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// fuelCellTailLength 探针温度之后的氢系统字段长度: 最高温度 2 + 探针 1 + 最高浓度 2 + 传感器 1 + 最高压力 2 + 传感器 1 + DC/DC 1
+const fuelCellTailLength = 10
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *FuelCellData) Length() int {
+	return 8 + int(d.TempProbeCount) + fuelCellTailLength
 }
 
-// ParseFuelCellData 解析燃料电池数据
+// ParseFuelCellData 解析燃料电池数据 (2016 版)
+// 格式: [电压 2][电流 2][燃料消耗率 2][探针总数 N 2][探针温度 N][氢系统最高温度 2][探针代号 1]
+// [氢气最高浓度 2][传感器代号 1][氢气最高压力 2][传感器代号 1][高压 DC/DC 状态 1]
 func ParseFuelCellData(data []byte) (*FuelCellData, error) {
 	// 最小长度: 2+2+2+2 = 8
 	if len(data) < 8 {
-		return nil, errors.New("燃料电池数据长度不足")
+		return nil, errShort("燃料电池数据长度不足", 0, 8, len(data))
 	}
 
 	voltRaw := binary.BigEndian.Uint16(data[0:2])
@@ -27,22 +45,47 @@ func ParseFuelCellData(data []byte) (*FuelCellData, error) {
 	rateRaw := binary.BigEndian.Uint16(data[4:6])
 	count := binary.BigEndian.Uint16(data[6:8])
 
-	expectedLen := 8 + int(count) // 假设每个探针 1 字节
+	expectedLen := 8 + int(count) + fuelCellTailLength // 每个探针 1 字节
 	if len(data) < expectedLen {
-		return nil, errors.New("燃料电池探针数据长度不足")
+		return nil, errShort("燃料电池探针数据长度不足", 8, expectedLen-8, len(data)-8)
 	}
 
-	temps := make([]byte, count)
-	// 偏移 8 开始读取 N 个字节
-	for i := 0; i < int(count); i++ {
-		temps[i] = data[8+i] - 40 // 偏移40
-	}
+	tail := data[8+int(count) : expectedLen]
+	maxTempRaw := binary.BigEndian.Uint16(tail[0:2])
+	pressureRaw := binary.BigEndian.Uint16(tail[6:8])
 
-	return &FuelCellData{
-		Voltage:         float32(voltRaw) * 0.1,
-		Current:         float32(currRaw) * 0.1,
-		FuelConsumeRate: float32(rateRaw) * 0.01,
+	f := &FuelCellData{
 		TempProbeCount:  count,
-		ProbeTemps:      temps,
-	}, nil
+		MaxTempProbe:    tail[2],
+		MaxH2ConcSensor: tail[5],
+		MaxH2PresSensor: tail[8],
+		DCDCStatus:      tail[9],
+		Raw:             rawCopy(data[:expectedLen]),
+	}
+	// 偏移 8 开始读取 N 个字节, 偏移40
+	f.ProbeTemps = f.Validity.temps("ProbeTemps", data[8:8+int(count)])
+	f.Validity.byteOK("MaxTempProbe", tail[2])
+	f.Validity.byteOK("MaxH2ConcSensor", tail[5])
+	f.Validity.byteOK("MaxH2PresSensor", tail[8])
+	f.Validity.byteOK("DCDCStatus", tail[9])
+
+	if f.Validity.wordOK("Voltage", voltRaw) {
+		f.Voltage = float32(voltRaw) * 0.1
+	}
+	if f.Validity.wordOK("Current", currRaw) {
+		f.Current = float32(currRaw) * 0.1
+	}
+	if f.Validity.wordOK("FuelConsumeRate", rateRaw) {
+		f.FuelConsumeRate = float32(rateRaw) * 0.01
+	}
+	if f.Validity.wordOK("MaxTemp", maxTempRaw) {
+		f.MaxTemp = float32(maxTempRaw)*0.1 - 40
+	}
+	if concRaw := binary.BigEndian.Uint16(tail[3:5]); f.Validity.wordOK("MaxH2Conc", concRaw) {
+		f.MaxH2Conc = concRaw
+	}
+	if f.Validity.wordOK("MaxH2Pressure", pressureRaw) {
+		f.MaxH2Pressure = float32(pressureRaw) * 0.1
+	}
+	return f, nil
 }
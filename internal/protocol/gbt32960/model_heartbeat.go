@@ -0,0 +1,18 @@
+package gbt32960
+
+// HeartbeatData 车辆心跳数据 (命令单元 0x07)
+// 标准规定心跳报文的数据单元为空，部分终端会附带多余字节，保留下来便于排查
+type HeartbeatData struct {
+	Extra []byte // 非标准的附加数据
+}
+
+// ParseHeartbeat 解析心跳数据
+// 格式: [空]
+func ParseHeartbeat(data []byte) (*HeartbeatData, error) {
+	hb := &HeartbeatData{}
+	if len(data) > 0 {
+		hb.Extra = make([]byte, len(data))
+		copy(hb.Extra, data)
+	}
+	return hb, nil
+}
@@ -2,29 +2,61 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
+)
+
+// 定位状态位
+const (
+	LocationInvalid = 0x01 // 位 0: 1=无效定位
+	LocationSouth   = 0x02 // 位 1: 1=南纬
+	LocationWest    = 0x04 // 位 2: 1=西经
 )
 
 // LocationData 车辆位置数据 (类型 0x05)
+// 经纬度为 WGS-84 坐标，南纬、西经为负值
 type LocationData struct {
 	State     byte    // 定位状态 (位 0:有效/无效, 位 1:南/北纬, 位 2:东/西经)
+	Valid     bool    // 定位有效
 	Longitude float64 // 经度, 精度 1e-6
 	Latitude  float64 // 纬度, 精度 1e-6
+
+	GCJ02 *Coordinate `json:",omitempty"` // 国测局坐标 (高德/腾讯), 按配置输出
+	BD09  *Coordinate `json:",omitempty"` // 百度坐标, 按配置输出
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *LocationData) Length() int {
+	return 9
 }
 
 // ParseLocationData 解析位置数据
 func ParseLocationData(data []byte) (*LocationData, error) {
 	if len(data) < 9 {
-		return nil, errors.New("位置数据长度不足")
+		return nil, errShort("位置数据长度不足", 0, 9, len(data))
 	}
 
 	// 状态(1) + 经度(4) + 纬度(4) = 9
 	longRaw := binary.BigEndian.Uint32(data[1:5])
 	latRaw := binary.BigEndian.Uint32(data[5:9])
 
-	return &LocationData{
-		State:     data[0],
-		Longitude: float64(longRaw) / 1000000.0,
-		Latitude:  float64(latRaw) / 1000000.0,
-	}, nil
+	l := &LocationData{State: data[0], Valid: data[0]&LocationInvalid == 0}
+	if l.Validity.dwordOK("Longitude", longRaw) {
+		l.Longitude = float64(longRaw) / 1000000.0
+		if l.State&LocationWest != 0 {
+			l.Longitude = -l.Longitude
+		}
+	}
+	if l.Validity.dwordOK("Latitude", latRaw) {
+		l.Latitude = float64(latRaw) / 1000000.0
+		if l.State&LocationSouth != 0 {
+			l.Latitude = -l.Latitude
+		}
+	}
+	return l, nil
+}
+
+// Convertible 是否可做坐标系转换 (定位有效且经纬度均非异常/无效值)
+func (l *LocationData) Convertible() bool {
+	return l.Valid && len(l.Validity) == 0
 }
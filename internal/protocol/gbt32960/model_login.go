@@ -2,44 +2,111 @@ package gbt32960
 
 import (
 	"encoding/binary"
+	"errors"
+	"fmt"
 	"time"
 )
 
+// loginFixedLength 车辆登入数据固定部分长度 (采集时间 + 流水号 + ICCID + 子系统数 + 编码长度)
+const loginFixedLength = 30
+
 // LoginData 车辆登入数据 (命令单元 0x01)
 type LoginData struct {
-	CollectTime time.Time // 数据采集时间
-	LoginSeq    uint16    // 登入流水号
-	Password    string    // ICCID (20字节)
-	SubSysCount byte      // 可充电储能子系统数
-	Coding      byte      // 可充电储能系统编码长度
+	CollectTime  time.Time           // 数据采集时间
+	LoginSeq     uint16              // 登入流水号
+	Password     string              // ICCID (20字节)
+	SubSysCount  byte                // 可充电储能子系统数 n
+	Coding       byte                // 可充电储能系统编码长度 m
+	StorageCodes []string            // 可充电储能系统编码, 按子系统顺序 (n 个, 每个 m 字节)
+	Extension    *LoginExtension2025 `json:",omitempty"` // 2025 版登入扩展字段
+	Trailing     RawBytes            `json:",omitempty"` // 格式之外的多余字节, 仅宽松模式下随消息原样发布
+}
+
+// LoginExtension2025 2025 版车辆登入扩展字段, 紧随编码列表之后
+// 格式: [车载终端硬件版本 5Byte][车载终端固件版本 5Byte], 编码与参数 0x07/0x08 一致, 不足补 0
+type LoginExtension2025 struct {
+	HardwareVersion string // 车载终端硬件版本
+	FirmwareVersion string // 车载终端固件版本
+}
+
+// loginExtensionLength 2025 版登入扩展字段长度
+const loginExtensionLength = 2 * versionLength
+
+// Marshal 编码登入扩展字段, 版本号超过 5 字节时返回错误
+func (e *LoginExtension2025) Marshal() ([]byte, error) {
+	if len(e.HardwareVersion) > versionLength || len(e.FirmwareVersion) > versionLength {
+		return nil, fmt.Errorf("版本号超过 %d 字节", versionLength)
+	}
+	return append(fixedString(e.HardwareVersion, versionLength), fixedString(e.FirmwareVersion, versionLength)...), nil
 }
 
-// ParseLogin 解析车辆登入数据
-// 格式: [采集时间 6Byte][登入流水号 2Byte][ICCID 20Byte][子系统数 1Byte][编码长度 1Byte]
+// ParseLogin 解析 2016 版车辆登入数据
+// 格式: [采集时间 6Byte][登入流水号 2Byte][ICCID 20Byte][子系统数 n 1Byte][编码长度 m 1Byte][编码 n*m Byte]
+// 编码列表之后存在多余字节时，同时返回解析结果 (多余字节保存在 Trailing) 与 ParseError
 func ParseLogin(data []byte) (*LoginData, error) {
-	//if len(data) < 30 {
-	//	return nil, errors.New("登入数据长度不足")
-	//}
+	login, end, err := parseLoginCommon(data)
+	if err != nil {
+		return nil, err
+	}
+	return login, login.trailing(data, end)
+}
 
-	// 解析时间 (6字节: 年 月 日 时 分 秒)
-	year := int(data[0]) + 2000
-	month := time.Month(data[1])
-	day := int(data[2])
-	hour := int(data[3])
-	minute := int(data[4])
-	second := int(data[5])
+// ParseLogin2025 解析 2025 版车辆登入数据
+// 格式: 同 2016 版, 编码列表之后追加扩展字段 [硬件版本 5Byte][固件版本 5Byte]
+func ParseLogin2025(data []byte) (*LoginData, error) {
+	login, end, err := parseLoginCommon(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < end+loginExtensionLength {
+		return nil, errShort("登入扩展字段长度不足", end, loginExtensionLength, len(data)-end)
+	}
+	login.Extension = &LoginExtension2025{
+		HardwareVersion: string(trimNulls(data[end : end+versionLength])),
+		FirmwareVersion: string(trimNulls(data[end+versionLength : end+loginExtensionLength])),
+	}
+	end += loginExtensionLength
+	return login, login.trailing(data, end)
+}
 
-	loc, _ := time.LoadLocation("Asia/Shanghai")
-	t := time.Date(year, month, day, hour, minute, second, 0, loc)
+// parseLoginCommon 解析两版共有的固定部分与编码列表，返回已解析的长度
+func parseLoginCommon(data []byte) (*LoginData, int, error) {
+	if len(data) < loginFixedLength {
+		return nil, 0, errors.New("登入数据长度不足")
+	}
 
-	seq := binary.BigEndian.Uint16(data[6:8])
-	password := string(data[8:28]) // ICCID
+	t, err := ParseCollectTime(data[0:6])
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return &LoginData{
+	login := &LoginData{
 		CollectTime: t,
-		LoginSeq:    seq,
-		Password:    password,
+		LoginSeq:    binary.BigEndian.Uint16(data[6:8]),
+		Password:    string(trimNulls(data[8:28])), // ICCID
 		SubSysCount: data[28],
 		Coding:      data[29],
-	}, nil
+	}
+
+	n, m := int(login.SubSysCount), int(login.Coding)
+	end := loginFixedLength + n*m
+	if len(data) < end {
+		return nil, 0, fmt.Errorf("可充电储能系统编码长度不足: 需要 %d 字节, 实际 %d", n*m, len(data)-loginFixedLength)
+	}
+
+	login.StorageCodes = make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		off := loginFixedLength + i*m
+		login.StorageCodes = append(login.StorageCodes, string(trimNulls(data[off:off+m])))
+	}
+	return login, end, nil
+}
+
+// trailing 记录 end 之后的多余字节，存在时返回 ParseError
+func (l *LoginData) trailing(data []byte, end int) error {
+	if len(data) == end {
+		return nil
+	}
+	l.Trailing = rawCopy(data[end:])
+	return &ParseError{Offset: end, Expected: end, Actual: len(data), Reason: "登入数据存在多余字节"}
 }
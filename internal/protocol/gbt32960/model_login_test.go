@@ -0,0 +1,80 @@
+package gbt32960
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// loginBody 构造车辆登入数据: 固定部分 + 编码列表 + extra
+func loginBody(codes []string, m int, extra ...byte) []byte {
+	data := make([]byte, loginFixedLength+len(codes)*m)
+	copy(data, EncodeTime(time.Date(2026, 10, 16, 8, 30, 0, 0, time.Local)))
+	binary.BigEndian.PutUint16(data[6:8], 7)
+	copy(data[8:28], "89860012345678901234")
+	data[28], data[29] = byte(len(codes)), byte(m)
+	for i, c := range codes {
+		copy(data[loginFixedLength+i*m:], c)
+	}
+	return append(data, extra...)
+}
+
+func TestParseLogin(t *testing.T) {
+	ext, err := (&LoginExtension2025{HardwareVersion: "H1.00", FirmwareVersion: "F2"}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := []string{"BP0123456789", "BP98765"}
+
+	tests := []struct {
+		name     string
+		parse    func([]byte) (*LoginData, error)
+		data     []byte
+		ext      *LoginExtension2025
+		trailing []byte
+		wantErr  bool // 无解析结果的错误
+	}{
+		{"2016", ParseLogin, loginBody(codes, 12), nil, nil, false},
+		{"2016 多余字节", ParseLogin, loginBody(codes, 12, 0xAA, 0xBB), nil, []byte{0xAA, 0xBB}, false},
+		{"2016 编码不足", ParseLogin, loginBody(codes, 12)[:40], nil, nil, true},
+		{"2025", ParseLogin2025, loginBody(codes, 12, ext...), &LoginExtension2025{HardwareVersion: "H1.00", FirmwareVersion: "F2"}, nil, false},
+		{"2025 多余字节", ParseLogin2025, loginBody(codes, 12, append(ext, 0xCC)...), &LoginExtension2025{HardwareVersion: "H1.00", FirmwareVersion: "F2"}, []byte{0xCC}, false},
+		{"2025 缺少扩展字段", ParseLogin2025, loginBody(codes, 12, ext[:4]...), nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parse(tt.data)
+			if tt.wantErr {
+				if err == nil || got != nil {
+					t.Fatalf("期望解析失败, 实际 %+v, %v", got, err)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("解析失败: %v", err)
+			}
+			var pe *ParseError
+			if (len(tt.trailing) > 0) != errors.As(err, &pe) {
+				t.Errorf("多余字节错误 = %v", err)
+			}
+			if !bytes.Equal(got.Trailing, tt.trailing) {
+				t.Errorf("Trailing = %x, 期望 %x", got.Trailing, tt.trailing)
+			}
+			if got.LoginSeq != 7 || got.Password != "89860012345678901234" || !reflect.DeepEqual(got.StorageCodes, codes) {
+				t.Errorf("登入数据 = %+v", got)
+			}
+			if !reflect.DeepEqual(got.Extension, tt.ext) {
+				t.Errorf("Extension = %+v, 期望 %+v", got.Extension, tt.ext)
+			}
+		})
+	}
+}
+
+func TestLoginExtensionRejectsLongVersion(t *testing.T) {
+	if _, err := (&LoginExtension2025{HardwareVersion: "H1.000"}).Marshal(); err == nil {
+		t.Error("超过 5 字节的版本号应返回错误")
+	}
+}
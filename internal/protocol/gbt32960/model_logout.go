@@ -19,15 +19,10 @@ func ParseLogout(data []byte) (*LogoutData, error) {
 		return nil, errors.New("登出数据长度不足")
 	}
 
-	year := int(data[0]) + 2000
-	month := time.Month(data[1])
-	day := int(data[2])
-	hour := int(data[3])
-	minute := int(data[4])
-	second := int(data[5])
-
-	loc, _ := time.LoadLocation("Asia/Shanghai")
-	t := time.Date(year, month, day, hour, minute, second, 0, loc)
+	t, err := ParseCollectTime(data[0:6])
+	if err != nil {
+		return nil, err
+	}
 
 	seq := binary.BigEndian.Uint16(data[6:8])
 
@@ -2,19 +2,21 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
 )
 
 // MotorUnit 单个驱动电机数据
 type MotorUnit struct {
-	Seq      byte    // 电机序号
-	Status   byte    // 电机状态 (0x01:耗电, 0x02:发电, 0x03:关闭, 0x04:准备)
-	CtrlTemp byte    // 控制器温度 (℃), 偏移40℃ (0=-40℃)
-	Speed    int16   // 电机转速 (r/min), 偏移20000 (0=-20000) -> 实际上是有符号还是无符号偏移? 标准说是 偏移量20000r/min。范围0~65531表示-20000~45531
-	Torque   float32 // 电机转矩 (N·m), 偏移2000N·m, 精度0.1
-	Temp     byte    // 电机温度 (℃), 偏移40℃
-	Voltage  float32 // 电机控制器输入电压 (V), 精度0.1
-	Current  float32 // 电机控制器直流母线电流 (A), 偏移1000A, 精度0.1
+	Seq      byte     // 电机序号
+	Status   byte     // 电机状态 (0x01:耗电, 0x02:发电, 0x03:关闭, 0x04:准备)
+	CtrlTemp int16    // 控制器温度 (℃), 偏移40℃ (0=-40℃)
+	Speed    int32    // 电机转速 (r/min), 偏移20000r/min, 范围 0~65531 表示 -20000~45531
+	Torque   float32  // 电机转矩 (N·m), 偏移2000N·m, 精度0.1
+	Temp     int16    // 电机温度 (℃), 偏移40℃
+	Voltage  float32  // 电机控制器输入电压 (V), 精度0.1
+	Current  float32  // 电机控制器直流母线电流 (A), 偏移1000A, 精度0.1
+	Raw      RawBytes // 原始字节 (12 字节)
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
 // MotorData 驱动电机数据 (类型 0x02)
@@ -23,17 +25,22 @@ type MotorData struct {
 	MotorList []MotorUnit // 电机列表
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *MotorData) Length() int {
+	return 1 + int(d.Count)*12
+}
+
 // ParseMotorData 解析驱动电机数据
 func ParseMotorData(data []byte) (*MotorData, error) {
 	if len(data) < 1 {
-		return nil, errors.New("电机数据为空")
+		return nil, errShort("电机数据为空", 0, 1, 0)
 	}
 	count := data[0]
 	// 每个电机数据长度 12 字节
 	// 序号(1) + 状态(1) + 控制器温度(1) + 转速(2) + 转矩(2) + 温度(1) + 电压(2) + 电流(2) = 12
 	expectedLen := 1 + int(count)*12
 	if len(data) < expectedLen {
-		return nil, errors.New("电机数据长度不匹配")
+		return nil, errShort("电机数据长度不足", 1, expectedLen-1, len(data)-1)
 	}
 
 	list := make([]MotorUnit, 0, count)
@@ -47,17 +54,31 @@ func ParseMotorData(data []byte) (*MotorData, error) {
 		currRaw := binary.BigEndian.Uint16(chunk[10:12])
 
 		unit := MotorUnit{
-			Seq:      chunk[0],
-			Status:   chunk[1],
-			CtrlTemp: chunk[2] - 40,
+			Seq:    chunk[0],
+			Status: chunk[1],
+			Raw:    rawCopy(chunk),
+		}
+		unit.Validity.byteOK("Status", chunk[1])
+		if unit.Validity.byteOK("CtrlTemp", chunk[2]) {
+			unit.CtrlTemp = offsetTemp(chunk[2])
+		}
+		if unit.Validity.wordOK("Speed", speedRaw) {
 			// 转速: 0 表示 -20000
-			Speed: int16(int(speedRaw) - 20000),
+			unit.Speed = int32(speedRaw) - 20000
+		}
+		if unit.Validity.wordOK("Torque", torqueRaw) {
 			// 转矩: 0 表示 -2000, 精度 0.1
-			Torque:  (float32(torqueRaw) * 0.1) - 2000.0,
-			Temp:    chunk[7] - 40,
-			Voltage: float32(voltRaw) * 0.1,
+			unit.Torque = (float32(torqueRaw) * 0.1) - 2000.0
+		}
+		if unit.Validity.byteOK("Temp", chunk[7]) {
+			unit.Temp = offsetTemp(chunk[7])
+		}
+		if unit.Validity.wordOK("Voltage", voltRaw) {
+			unit.Voltage = float32(voltRaw) * 0.1
+		}
+		if unit.Validity.wordOK("Current", currRaw) {
 			// 电流: 0 表示 -1000, 精度 0.1
-			Current: (float32(currRaw) * 0.1) - 1000.0,
+			unit.Current = (float32(currRaw) * 0.1) - 1000.0
 		}
 		list = append(list, unit)
 		offset += 12
@@ -0,0 +1,280 @@
+package gbt32960
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ParamID 车载终端参数 ID (参数查询 0x80 / 参数设置 0x81)
+type ParamID byte
+
+const (
+	ParamLocalStoragePeriod   ParamID = 0x01 // 车载终端本地存储时间周期 (ms), WORD
+	ParamReportInterval       ParamID = 0x02 // 正常时信息上报时间周期 (s), WORD
+	ParamAlarmReportInterval  ParamID = 0x03 // 出现报警时信息上报时间周期 (ms), WORD
+	ParamPlatformDomainLength ParamID = 0x04 // 远程服务与管理平台域名长度 m, BYTE
+	ParamPlatformDomain       ParamID = 0x05 // 远程服务与管理平台域名, STRING[m]
+	ParamPlatformPort         ParamID = 0x06 // 远程服务与管理平台端口, WORD
+	ParamHardwareVersion      ParamID = 0x07 // 硬件版本, STRING[5]
+	ParamFirmwareVersion      ParamID = 0x08 // 固件版本, STRING[5]
+	ParamHeartbeatInterval    ParamID = 0x09 // 车载终端心跳发送周期 (s), BYTE
+	ParamTerminalRespTimeout  ParamID = 0x0A // 终端应答超时时间 (s), WORD
+	ParamPlatformRespTimeout  ParamID = 0x0B // 平台应答超时时间 (s), WORD
+	ParamLoginRetryInterval   ParamID = 0x0C // 连续三次登入失败后到下一次登入的间隔 (min), BYTE
+	ParamPublicDomainLength   ParamID = 0x0D // 公共平台域名长度 n, BYTE
+	ParamPublicDomain         ParamID = 0x0E // 公共平台域名, STRING[n]
+	ParamPublicPort           ParamID = 0x0F // 公共平台端口, WORD
+	ParamSamplingMonitor      ParamID = 0x10 // 是否处于抽样监测中 (0x01:是, 0x02:否), BYTE
+)
+
+// versionLength 硬件/固件版本字段固定长度
+const versionLength = 5
+
+// TerminalParam 单个终端参数项
+// Value 的类型由 ID 决定: WORD -> uint16, BYTE -> byte, STRING -> string
+type TerminalParam struct {
+	ID    ParamID
+	Value interface{}
+}
+
+// ParamQueryData 参数查询应答数据 (命令单元 0x80)
+type ParamQueryData struct {
+	CollectTime time.Time       // 参数查询时间
+	Count       byte            // 参数总数
+	Params      []TerminalParam // 参数项列表
+}
+
+// BuildParamQuery 构建参数查询命令数据单元
+// 格式: [查询时间 6][参数总数 1][参数 ID 列表 N]
+func BuildParamQuery(now time.Time, ids []ParamID) []byte {
+	data := make([]byte, 0, 7+len(ids))
+	data = append(data, EncodeTime(now)...)
+	data = append(data, byte(len(ids)))
+	for _, id := range ids {
+		data = append(data, byte(id))
+	}
+	return data
+}
+
+// ParseParamQueryResponse 解析参数查询应答
+// 格式: [查询时间 6][参数总数 1][参数项列表: [ID 1][值 M]...]
+func ParseParamQueryResponse(data []byte) (*ParamQueryData, error) {
+	if len(data) < 7 {
+		return nil, errors.New("参数查询应答长度不足")
+	}
+
+	t, err := ParseCollectTime(data[0:6])
+	if err != nil {
+		return nil, err
+	}
+	count := data[6]
+
+	params, err := decodeParams(data[7:], int(count))
+	if err != nil {
+		return nil, err
+	}
+
+	return &ParamQueryData{
+		CollectTime: t,
+		Count:       count,
+		Params:      params,
+	}, nil
+}
+
+// decodeParams 依次解析 count 个参数项
+// 域名 (0x05/0x0E) 的长度取自同一报文中先出现的长度参数 (0x04/0x0D)
+func decodeParams(data []byte, count int) ([]TerminalParam, error) {
+	params := make([]TerminalParam, 0, count)
+	lengths := make(map[ParamID]int)
+	offset := 0
+
+	for i := 0; i < count; i++ {
+		if offset >= len(data) {
+			return nil, fmt.Errorf("参数项不完整 (第 %d 项)", i+1)
+		}
+		id := ParamID(data[offset])
+		offset++
+
+		var width int
+		switch id {
+		case ParamLocalStoragePeriod, ParamReportInterval, ParamAlarmReportInterval,
+			ParamPlatformPort, ParamTerminalRespTimeout, ParamPlatformRespTimeout, ParamPublicPort:
+			width = 2
+		case ParamPlatformDomainLength, ParamHeartbeatInterval, ParamLoginRetryInterval,
+			ParamPublicDomainLength, ParamSamplingMonitor:
+			width = 1
+		case ParamHardwareVersion, ParamFirmwareVersion:
+			width = versionLength
+		case ParamPlatformDomain, ParamPublicDomain:
+			n, ok := lengths[id-1]
+			if !ok {
+				return nil, fmt.Errorf("参数 0x%02X 缺少对应的长度参数 0x%02X", byte(id), byte(id-1))
+			}
+			width = n
+		default:
+			// 自定义参数长度未知，无法继续解析
+			return nil, fmt.Errorf("无法解析的参数 ID: 0x%02X", byte(id))
+		}
+
+		if len(data) < offset+width {
+			return nil, fmt.Errorf("参数 0x%02X 数据长度不足", byte(id))
+		}
+		raw := data[offset : offset+width]
+		offset += width
+
+		var value interface{}
+		switch {
+		case id == ParamPlatformDomain || id == ParamPublicDomain ||
+			id == ParamHardwareVersion || id == ParamFirmwareVersion:
+			value = string(trimNulls(raw))
+		case width == 2:
+			value = binary.BigEndian.Uint16(raw)
+		default:
+			value = raw[0]
+			if id == ParamPlatformDomainLength || id == ParamPublicDomainLength {
+				lengths[id] = int(raw[0])
+			}
+		}
+		params = append(params, TerminalParam{ID: id, Value: value})
+	}
+
+	return params, nil
+}
+
+// ParamSetResult 单个参数的设置结果
+type ParamSetResult struct {
+	ID      ParamID
+	Success bool
+}
+
+// BuildParamSet 构建参数设置命令数据单元
+// 格式: [设置时间 6][参数总数 1][参数项列表: [ID 1][值 M]...]
+// 参数项按 ID 升序排列，设置域名 (0x05/0x0E) 时自动补充对应的长度参数 (0x04/0x0D)，
+// 此时调用方不得再自行指定长度参数，否则返回错误
+func BuildParamSet(now time.Time, params map[ParamID]interface{}) ([]byte, error) {
+	if len(params) == 0 {
+		return nil, errors.New("参数设置列表为空")
+	}
+
+	values := make(map[ParamID]interface{}, len(params)+2)
+	for id, v := range params {
+		values[id] = v
+	}
+	for _, domainID := range []ParamID{ParamPlatformDomain, ParamPublicDomain} {
+		if v, ok := values[domainID]; ok {
+			domain, isString := v.(string)
+			if !isString {
+				return nil, fmt.Errorf("参数 0x%02X 应为 string, 实际为 %T", byte(domainID), v)
+			}
+			if len(domain) > 0xFF {
+				return nil, fmt.Errorf("参数 0x%02X 域名过长: %d", byte(domainID), len(domain))
+			}
+			if _, set := params[domainID-1]; set {
+				return nil, fmt.Errorf("参数 0x%02X 由域名 0x%02X 自动计算, 不能单独设置", byte(domainID-1), byte(domainID))
+			}
+			values[domainID-1] = byte(len(domain))
+		}
+	}
+	if len(values) > 0xFF {
+		return nil, fmt.Errorf("参数个数过多: %d", len(values))
+	}
+
+	data := make([]byte, 0, 64)
+	data = append(data, EncodeTime(now)...)
+	data = append(data, byte(len(values)))
+	for id := 0; id <= 0xFF; id++ {
+		v, ok := values[ParamID(id)]
+		if !ok {
+			continue
+		}
+		item, err := encodeParam(ParamID(id), v)
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, item...)
+	}
+	return data, nil
+}
+
+// encodeParam 编码单个参数项 [ID 1][值 M]
+func encodeParam(id ParamID, value interface{}) ([]byte, error) {
+	typeErr := func(want string) error {
+		return fmt.Errorf("参数 0x%02X 应为 %s, 实际为 %T", byte(id), want, value)
+	}
+
+	switch id {
+	case ParamLocalStoragePeriod, ParamReportInterval, ParamAlarmReportInterval,
+		ParamPlatformPort, ParamTerminalRespTimeout, ParamPlatformRespTimeout, ParamPublicPort:
+		v, ok := value.(uint16)
+		if !ok {
+			return nil, typeErr("uint16")
+		}
+		return []byte{byte(id), byte(v >> 8), byte(v)}, nil
+
+	case ParamPlatformDomainLength, ParamHeartbeatInterval, ParamLoginRetryInterval,
+		ParamPublicDomainLength, ParamSamplingMonitor:
+		v, ok := value.(byte)
+		if !ok {
+			return nil, typeErr("byte")
+		}
+		return []byte{byte(id), v}, nil
+
+	case ParamHardwareVersion, ParamFirmwareVersion:
+		v, ok := value.(string)
+		if !ok {
+			return nil, typeErr("string")
+		}
+		if len(v) > versionLength {
+			return nil, fmt.Errorf("参数 0x%02X 版本号超过 %d 字节", byte(id), versionLength)
+		}
+		item := make([]byte, 1+versionLength)
+		item[0] = byte(id)
+		copy(item[1:], v)
+		return item, nil
+
+	case ParamPlatformDomain, ParamPublicDomain:
+		v, ok := value.(string)
+		if !ok {
+			return nil, typeErr("string")
+		}
+		return append([]byte{byte(id)}, v...), nil
+
+	default:
+		return nil, fmt.Errorf("不支持设置的参数 ID: 0x%02X", byte(id))
+	}
+}
+
+// ParseParamSetResponse 解析参数设置应答，结合应答标志给出每个参数的设置结果
+// 标准应答仅回显设置时间，整体成功或失败由应答标志决定；
+// 若终端在应答中回显了参数项列表，则以回显的参数为成功、缺失的参数为失败
+func ParseParamSetResponse(response byte, data []byte, requested []ParamID) ([]ParamSetResult, error) {
+	results := make([]ParamSetResult, 0, len(requested))
+	if response != 0x01 {
+		for _, id := range requested {
+			results = append(results, ParamSetResult{ID: id, Success: false})
+		}
+		return results, nil
+	}
+
+	if len(data) <= 7 {
+		for _, id := range requested {
+			results = append(results, ParamSetResult{ID: id, Success: true})
+		}
+		return results, nil
+	}
+
+	echoed, err := decodeParams(data[7:], int(data[6]))
+	if err != nil {
+		return nil, fmt.Errorf("参数设置应答解析失败: %v", err)
+	}
+	accepted := make(map[ParamID]bool, len(echoed))
+	for _, p := range echoed {
+		accepted[p.ID] = true
+	}
+	for _, id := range requested {
+		results = append(results, ParamSetResult{ID: id, Success: accepted[id]})
+	}
+	return results, nil
+}
@@ -0,0 +1,28 @@
+package gbt32960
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestBuildParamSetDomainLength(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 30, 0, 0, time.Local)
+	data, err := BuildParamSet(now, map[ParamID]interface{}{ParamPlatformDomain: "gw.example.com"})
+	if err != nil {
+		t.Fatalf("BuildParamSet: %v", err)
+	}
+	want := append(EncodeTime(now), 0x02, byte(ParamPlatformDomainLength), 14, byte(ParamPlatformDomain))
+	want = append(want, "gw.example.com"...)
+	if !bytes.Equal(data, want) {
+		t.Errorf("数据单元 = %x, 期望 %x", data, want)
+	}
+
+	_, err = BuildParamSet(now, map[ParamID]interface{}{
+		ParamPlatformDomain:       "gw.example.com",
+		ParamPlatformDomainLength: byte(3),
+	})
+	if err == nil {
+		t.Error("同时指定域名与域名长度应返回错误")
+	}
+}
@@ -6,8 +6,9 @@ import (
 
 // PlatformLoginData 平台登入数据 (命令单元 0x05)
 type PlatformLoginData struct {
-	Username string
-	Password string
+	Username    string
+	Password    string
+	EncryptRule byte // 加密规则 (0x01:不加密, 0x02:RSA, ...), 缺省为 0x01
 }
 
 func ParsePlatformLogin(data []byte) (*PlatformLoginData, error) {
@@ -35,9 +36,16 @@ func ParsePlatformLogin(data []byte) (*PlatformLoginData, error) {
 	password := string(trimNulls(passBytes)) // Usually password might not have nulls if it matches length, but safe to trim
 	offset += 20
 
+	// EncryptRule: 1 Byte
+	encryptRule := EncryptionNone
+	if len(data) > offset {
+		encryptRule = data[offset]
+	}
+
 	return &PlatformLoginData{
-		Username: username,
-		Password: password,
+		Username:    username,
+		Password:    password,
+		EncryptRule: encryptRule,
 	}, nil
 }
 
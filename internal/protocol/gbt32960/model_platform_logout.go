@@ -0,0 +1,31 @@
+package gbt32960
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// PlatformLogoutData 平台登出数据 (命令单元 0x06)
+type PlatformLogoutData struct {
+	CollectTime time.Time // 登出时间
+	LogoutSeq   uint16    // 登出流水号 (与平台登入流水号一致)
+}
+
+// ParsePlatformLogout 解析平台登出数据
+// 格式: [登出时间 6Byte][登出流水号 2Byte]
+func ParsePlatformLogout(data []byte) (*PlatformLogoutData, error) {
+	if len(data) < 8 {
+		return nil, errors.New("平台登出数据长度不足")
+	}
+
+	t, err := ParseCollectTime(data[0:6])
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlatformLogoutData{
+		CollectTime: t,
+		LogoutSeq:   binary.BigEndian.Uint16(data[6:8]),
+	}, nil
+}
@@ -1,5 +1,11 @@
 package gbt32960
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
 // RealTimeDataType 定义实时数据类型
 // 0x01: 整车数据
 // 0x02: 驱动电机数据
@@ -28,3 +34,48 @@ const (
 	DataTypeSuperCap        RealTimeDataType = 0x31 // 超级电容数据
 	DataTypeSuperCapExtreme RealTimeDataType = 0x32 // 超级电容极值数据
 )
+
+// collectTimeLocation 采集时间所在时区 (GB/T 32960 采用 GMT+8)
+var collectTimeLocation = time.FixedZone("CST", 8*3600)
+
+// SetTimeZone 设置采集时间的编解码时区，应在启动时、处理报文前调用
+func SetTimeZone(loc *time.Location) {
+	if loc != nil {
+		collectTimeLocation = loc
+	}
+}
+
+// TimeZone 返回采集时间的编解码时区
+func TimeZone() *time.Location {
+	return collectTimeLocation
+}
+
+// CheckCollectTime 校验 6 字节采集时间的各字段是否在日历范围内 (如月份 13、2 月 30 日)
+// ParseCollectTime 对越界字段按 time.Date 规则顺延，不返回错误
+func CheckCollectTime(data []byte) error {
+	if len(data) < 6 {
+		return errors.New("采集时间长度不足")
+	}
+	month, day := int(data[1]), int(data[2])
+	if month < 1 || month > 12 {
+		return fmt.Errorf("采集时间月份越界: %d", month)
+	}
+	days := time.Date(int(data[0])+2000, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day < 1 || day > days {
+		return fmt.Errorf("采集时间日期越界: %d-%d", month, day)
+	}
+	if data[3] > 23 || data[4] > 59 || data[5] > 59 {
+		return fmt.Errorf("采集时间时分秒越界: %02d:%02d:%02d", data[3], data[4], data[5])
+	}
+	return nil
+}
+
+// ParseCollectTime 解析 6 字节采集时间
+// 格式: [年 1][月 1][日 1][时 1][分 1][秒 1], 年份为 2000 年起的偏移
+func ParseCollectTime(data []byte) (time.Time, error) {
+	if len(data) < 6 {
+		return time.Time{}, errors.New("采集时间长度不足")
+	}
+	return time.Date(int(data[0])+2000, time.Month(data[1]), int(data[2]),
+		int(data[3]), int(data[4]), int(data[5]), 0, collectTimeLocation), nil
+}
@@ -2,7 +2,6 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
 )
 
 // BatteryVoltageData 动力蓄电池最小并联单元电压数据 (类型 0x07)
@@ -12,6 +11,15 @@ type BatteryVoltageData struct {
 	PackVoltages     []BatteryPackInfo // 电池包电压信息列表
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *BatteryVoltageData) Length() int {
+	n := 1
+	for _, p := range d.PackVoltages {
+		n += 7 + int(p.SingleCellCount)*2
+	}
+	return n
+}
+
 // BatteryPackInfo 单个电池包电压信息 (表12)
 type BatteryPackInfo struct {
 	PackSeq           byte      // 动力蓄电池包号 (1~50)
@@ -19,6 +27,9 @@ type BatteryPackInfo struct {
 	Current           float32   // 动力蓄电池包电流 (A) 0.1 (偏移3000A)
 	SingleCellCount   uint16    // 最小并联单元总数 N (单体总数) (1~65531)
 	FrameCellVoltages []float32 // 本帧最小并联单元电压 (V) 0.01 (2*N bytes)
+	Raw               RawBytes  // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
 // BatteryTempData 动力蓄电池温度数据 (类型 0x08)
@@ -28,11 +39,23 @@ type BatteryTempData struct {
 	PackTemps        []BatteryPackTemp // 电池包温度信息列表
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *BatteryTempData) Length() int {
+	n := 1
+	for _, p := range d.PackTemps {
+		n += 3 + int(p.ProbeCount)
+	}
+	return n
+}
+
 // BatteryPackTemp 单个电池包温度信息 (表14)
 type BatteryPackTemp struct {
-	PackSeq    byte   // 动力蓄电池包号 (1~50)
-	ProbeCount uint16 // 动力蓄电池包温度探针个数 N (1~65531)
-	ProbeTemps []byte // 探针温度 (N bytes) (偏移40)
+	PackSeq    byte     // 动力蓄电池包号 (1~50)
+	ProbeCount uint16   // 动力蓄电池包温度探针个数 N (1~65531)
+	ProbeTemps []int16  // 探针温度 (℃) (N bytes) (偏移40)
+	Raw        RawBytes // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
 // StorageVoltageData2016 可充电储能装置电压数据 (2016标准: 类型 0x08)
@@ -55,37 +78,46 @@ type StorageVoltageData2016 struct {
 	Subsystems     []StorageSubsystemInfo
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *StorageVoltageData2016) Length() int {
+	n := 1
+	for _, s := range d.Subsystems {
+		n += 10 + int(s.FrameCellCount)*2
+	}
+	return n
+}
+
 type StorageSubsystemInfo struct {
-	SystemNo        byte    // 子系统号
-	Voltage         float32 // 0.1V
-	Current         float32 // 0.1A, Offset 1000A
-	SingleCellCount uint16  // 单体电池总数
-	StartFrameSeq   uint16  // 本帧起始电池序号
-	FrameCellCount  byte    // 本帧单体电池总数
-	CellVoltages    []float32
+	SystemNo        byte      // 子系统号
+	Voltage         float32   // 0.1V
+	Current         float32   // 0.1A, Offset 1000A
+	SingleCellCount uint16    // 单体电池总数
+	StartFrameSeq   uint16    // 本帧起始电池序号
+	FrameCellCount  byte      // 本帧单体电池总数
+	CellVoltages    []float32 // 单体电池电压 (V) 0.001
+	Raw             RawBytes  // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
 // ParseStorageVoltageData2016 解析2016版储能电压数据 (0x08)
+// 数据截断时返回已解析的子系统 (末个子系统的单体电压可能不完整) 与 ParseError
 func ParseStorageVoltageData2016(data []byte) (*StorageVoltageData2016, error) {
 	// Min Header: Count(1)
 	if len(data) < 1 {
-		return nil, errors.New("储能装置电压数据长度不足(Header)")
+		return nil, errShort("储能装置电压数据长度不足(Header)", 0, 1, 0)
 	}
 	count := data[0]
-	// Sanity validity
-	if count > 250 { // Just some check
-		// warning?
-	}
-
-	sysList := make([]StorageSubsystemInfo, 0, int(count))
+	out := &StorageVoltageData2016{SubsystemCount: count, Subsystems: make([]StorageSubsystemInfo, 0, int(count))}
 	offset := 1
 
 	for i := 0; i < int(count); i++ {
 		// Subsystem Frame Header Min: 1+2+2+2+2+1 = 10 bytes
 		if len(data) < offset+10 {
-			break
+			return out, errShort("储能子系统电压数据头不完整", offset, 10, len(data)-offset)
 		}
 
+		start := offset
 		sysNo := data[offset]
 		vol := binary.BigEndian.Uint16(data[offset+1 : offset+3])
 		cur := binary.BigEndian.Uint16(data[offset+3 : offset+5])
@@ -99,57 +131,55 @@ func ParseStorageVoltageData2016(data []byte) (*StorageVoltageData2016, error) {
 		needed := int(frameCells) * 2
 		avail := len(data) - offset
 		readBytes := needed
-		realCount := int(frameCells)
 		if avail < needed {
 			readBytes = avail / 2 * 2
-			realCount = readBytes / 2
 		}
 
-		cells := make([]float32, 0, realCount)
-		for j := 0; j < realCount; j++ {
-			raw := binary.BigEndian.Uint16(data[offset+j*2 : offset+j*2+2])
-			cells = append(cells, float32(raw)*0.001) // 2016 Standard: 0.001V
-		}
-
-		sysList = append(sysList, StorageSubsystemInfo{
+		sub := StorageSubsystemInfo{
 			SystemNo:        sysNo,
-			Voltage:         float32(vol) * 0.1,
-			Current:         float32(cur)*0.1 - 1000.0, // 2016 Offset 1000A
 			SingleCellCount: totalCells,
 			StartFrameSeq:   startSeq,
 			FrameCellCount:  frameCells,
-			CellVoltages:    cells,
-		})
+			Raw:             rawCopy(data[start : offset+readBytes]),
+		}
+		sub.CellVoltages = sub.Validity.scaledWords("CellVoltages", data[offset:offset+readBytes], 0.001) // 2016 Standard: 0.001V
+		if sub.Validity.wordOK("Voltage", vol) {
+			sub.Voltage = float32(vol) * 0.1
+		}
+		if sub.Validity.wordOK("Current", cur) {
+			sub.Current = float32(cur)*0.1 - 1000.0 // 2016 Offset 1000A
+		}
+		out.Subsystems = append(out.Subsystems, sub)
+		if readBytes < needed {
+			return out, errShort("单体电池电压数据不完整", offset, needed, avail)
+		}
 
 		offset += readBytes
 	}
 
-	return &StorageVoltageData2016{SubsystemCount: count, Subsystems: sysList}, nil
+	return out, nil
 }
 
 // ParseBatteryVoltageData2025 解析动力蓄电池最小并联单元电压数据 (0x07 in 2025)
 // 结构: [包个数 1][包1][包2]...
 // 包结构: [包号 1][电压 2][电流 2][单体总数 2][单体电压 N*2]
+// 数据截断时返回已解析的电池包与 ParseError
 func ParseBatteryVoltageData2025(data []byte) (*BatteryVoltageData, error) {
 	if len(data) < 1 {
-		return nil, errors.New("动力蓄电池电压数据长度不足(Header)")
+		return nil, errShort("动力蓄电池电压数据长度不足(Header)", 0, 1, 0)
 	}
 
 	packCount := data[0]
-	// Sanity check
-	if packCount > 50 && packCount != 0xFE && packCount != 0xFF {
-		// Just a warning threshold, protocol says 0-50
-	}
-
-	packs := make([]BatteryPackInfo, 0, int(packCount))
+	out := &BatteryVoltageData{BatteryPackCount: packCount, PackVoltages: make([]BatteryPackInfo, 0, int(packCount))}
 
 	offset := 1
 	for i := 0; i < int(packCount); i++ {
 		// Check Min Length for Header of Pack: 1+2+2+2 = 7 bytes
 		if len(data) < offset+7 {
-			break // Stop parsing if incomplete
+			return out, errShort("动力蓄电池包电压数据头不完整", offset, 7, len(data)-offset)
 		}
 
+		start := offset
 		seq := data[offset]
 		voltRaw := binary.BigEndian.Uint16(data[offset+1 : offset+3])
 		currRaw := binary.BigEndian.Uint16(data[offset+3 : offset+5])
@@ -163,38 +193,35 @@ func ParseBatteryVoltageData2025(data []byte) (*BatteryVoltageData, error) {
 		// Bounds check
 		available := len(data) - offset
 		readBytes := cellsNeededBytes
-		realCount := int(countRaw)
 
 		if available < cellsNeededBytes {
 			// Truncate logic to avoid panic
 			readBytes = available
 			// Start align to 2
 			readBytes = readBytes / 2 * 2
-			realCount = readBytes / 2
 		}
 
-		cellVolts := make([]float32, 0, realCount)
-		for j := 0; j < realCount; j++ {
-			cOffset := offset + j*2
-			cVal := binary.BigEndian.Uint16(data[cOffset : cOffset+2])
-			cellVolts = append(cellVolts, float32(cVal)*0.01) // 2025 standard says 0.01V resolution! (Old was 0.001)
+		pack := BatteryPackInfo{
+			PackSeq:         seq,
+			SingleCellCount: countRaw,
+			Raw:             rawCopy(data[start : offset+readBytes]),
+		}
+		pack.FrameCellVoltages = pack.Validity.scaledWords("FrameCellVoltages", data[offset:offset+readBytes], 0.01) // 2025 standard says 0.01V resolution! (Old was 0.001)
+		if pack.Validity.wordOK("Voltage", voltRaw) {
+			pack.Voltage = float32(voltRaw) * 0.1
+		}
+		if pack.Validity.wordOK("Current", currRaw) {
+			pack.Current = float32(currRaw)*0.1 - 3000.0 // 2025 standard: Offset 3000A
+		}
+		out.PackVoltages = append(out.PackVoltages, pack)
+		if readBytes < cellsNeededBytes {
+			return out, errShort("最小并联单元电压数据不完整", offset, cellsNeededBytes, available)
 		}
-
-		packs = append(packs, BatteryPackInfo{
-			PackSeq:           seq,
-			Voltage:           float32(voltRaw) * 0.1,
-			Current:           float32(currRaw)*0.1 - 3000.0, // 2025 standard: Offset 3000A
-			SingleCellCount:   countRaw,
-			FrameCellVoltages: cellVolts,
-		})
 
 		offset += readBytes
 	}
 
-	return &BatteryVoltageData{
-		BatteryPackCount: packCount,
-		PackVoltages:     packs,
-	}, nil
+	return out, nil
 }
 
 // StorageTempData2016 可充电储能装置温度数据 (2016标准: 类型 0x09)
@@ -205,28 +232,41 @@ type StorageTempData2016 struct {
 	Subsystems     []StorageTempSubsystem
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *StorageTempData2016) Length() int {
+	n := 1
+	for _, s := range d.Subsystems {
+		n += 3 + int(s.ProbeCount)
+	}
+	return n
+}
+
 type StorageTempSubsystem struct {
 	SystemNo     byte
 	ProbeCount   uint16
-	Temperatures []byte // Offset 40
+	Temperatures []int16  // 探针温度 (℃), Offset 40, 异常/无效的探针为 0 并记录于 Validity
+	Raw          RawBytes // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
 // ParseStorageTempData2016 解析2016版温度数据 (0x09)
+// 数据截断时返回已解析的子系统与 ParseError
 func ParseStorageTempData2016(data []byte) (*StorageTempData2016, error) {
 	if len(data) < 1 {
-		return nil, errors.New("储能装置温度数据长度不足(Header)")
+		return nil, errShort("储能装置温度数据长度不足(Header)", 0, 1, 0)
 	}
 	count := data[0]
-
-	sysList := make([]StorageTempSubsystem, 0, int(count))
+	out := &StorageTempData2016{SubsystemCount: count, Subsystems: make([]StorageTempSubsystem, 0, int(count))}
 	offset := 1
 
 	for i := 0; i < int(count); i++ {
 		// Header: 1+2 = 3
 		if len(data) < offset+3 {
-			break
+			return out, errShort("储能子系统温度数据头不完整", offset, 3, len(data)-offset)
 		}
 
+		start := offset
 		sysNo := data[offset]
 		pCount := binary.BigEndian.Uint16(data[offset+1 : offset+3])
 		offset += 3
@@ -238,40 +278,42 @@ func ParseStorageTempData2016(data []byte) (*StorageTempData2016, error) {
 			readCount = avail
 		}
 
-		temps := make([]byte, readCount)
-		for j := 0; j < readCount; j++ {
-			temps[j] = data[offset+j] - 40
+		sub := StorageTempSubsystem{
+			SystemNo:   sysNo,
+			ProbeCount: pCount,
+			Raw:        rawCopy(data[start : offset+readCount]),
+		}
+		sub.Temperatures = sub.Validity.temps("Temperatures", data[offset:offset+readCount])
+		out.Subsystems = append(out.Subsystems, sub)
+		if readCount < needed {
+			return out, errShort("探针温度数据不完整", offset, needed, avail)
 		}
-
-		sysList = append(sysList, StorageTempSubsystem{
-			SystemNo:     sysNo,
-			ProbeCount:   pCount,
-			Temperatures: temps,
-		})
 		offset += readCount
 	}
 
-	return &StorageTempData2016{SubsystemCount: count, Subsystems: sysList}, nil
+	return out, nil
 }
 
 // ParseBatteryTempData2025 解析动力蓄电池温度数据 (0x08 in 2025)
 // 结构: [包个数 1][包1][包2]...
 // 包结构: [包号 1][探针个数 2][温度 N]
+// 数据截断时返回已解析的电池包与 ParseError
 func ParseBatteryTempData2025(data []byte) (*BatteryTempData, error) {
 	if len(data) < 1 {
-		return nil, errors.New("动力蓄电池温度数据长度不足(Header)")
+		return nil, errShort("动力蓄电池温度数据长度不足(Header)", 0, 1, 0)
 	}
 
 	packCount := data[0]
-	packs := make([]BatteryPackTemp, 0, int(packCount))
+	out := &BatteryTempData{BatteryPackCount: packCount, PackTemps: make([]BatteryPackTemp, 0, int(packCount))}
 
 	offset := 1
 	for i := 0; i < int(packCount); i++ {
 		// Header: 1+2 = 3 bytes
 		if len(data) < offset+3 {
-			break
+			return out, errShort("动力蓄电池包温度数据头不完整", offset, 3, len(data)-offset)
 		}
 
+		start := offset
 		seq := data[offset]
 		probeCount := binary.BigEndian.Uint16(data[offset+1 : offset+3])
 
@@ -285,24 +327,21 @@ func ParseBatteryTempData2025(data []byte) (*BatteryTempData, error) {
 			readCount = available
 		}
 
-		temps := make([]byte, readCount)
-		for j := 0; j < readCount; j++ {
-			temps[j] = data[offset+j] - 40
-		}
-
-		packs = append(packs, BatteryPackTemp{
+		pack := BatteryPackTemp{
 			PackSeq:    seq,
 			ProbeCount: probeCount,
-			ProbeTemps: temps,
-		})
+			Raw:        rawCopy(data[start : offset+readCount]),
+		}
+		pack.ProbeTemps = pack.Validity.temps("ProbeTemps", data[offset:offset+readCount])
+		out.PackTemps = append(out.PackTemps, pack)
+		if readCount < needed {
+			return out, errShort("探针温度数据不完整", offset, needed, available)
+		}
 
 		offset += readCount
 	}
 
-	return &BatteryTempData{
-		BatteryPackCount: packCount,
-		PackTemps:        packs,
-	}, nil
+	return out, nil
 }
 
 // FuelCellStackData 燃料电池电堆数据 (类型 0x30)
@@ -312,51 +351,46 @@ type FuelCellStackData struct {
 	Stacks     []FuelCellStackInfo
 }
 
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *FuelCellStackData) Length() int {
+	n := 1
+	for _, s := range d.Stacks {
+		n += 10 + int(s.ProbeCount)
+	}
+	return n
+}
+
+// FuelCellStackInfo 单个燃料电池电堆数据
 type FuelCellStackInfo struct {
-	StackSeq byte    // 电堆序号
-	Voltage  float32 // 电压 0.1V
-	Current  float32 // 电流 0.1A (注意：文档通常有偏移？？需确认。此处假设无偏移或标准偏移？
-	// 0x07电压Offset 3000A. 0x03燃料电池电流通常无偏移？
-	// Grep output didn't show current offset.
-	// Let's assume 0.1A raw for now or standard GBT 3k offset?
-	// Wait, 0x03 (Old Fuel Cell) had current.
-	// Let's check old model_fuel_cell.go for precedent.
-	// Old 0x03 Current was 0.1A, no offset mentioned in typical GBT?
-	// Actually OLD 0x03 had Voltage(0.1V), Current(0.1A).
-	// Table 19 output truncated "燃料电池电堆电流...".
-	// I will check 0x07 again: Current has 3000A offset.
-	// I will play safe: If GBT 2016 0x03 current had no offset, 2025 0x30 might also not?
-	// BUT 0x31 SuperCap Current HAS 3000A offset.
-	// 0x07 Storage Current HAS 3000A/1000A offset.
-	// I will assume 3000A offset for Energy Storage related currents usually.
-	// But Fuel Cell?
-	// Let's check 0x31 definition again. "数值偏移量3000A".
-	// Let's check 0x30 definition from previous grep...
-	// It was cut off.
-	// I'll take a safe guess: standard high voltage current usually has offset.
-	// But I'll stick to 0.1 multiplier. If offset needed, I can fix later.
-	// Let's use 0.1 * val.
-	AirInPressure  float32 // 空气入口压力 0.1kPa, offset -100kPa
-	AirInTemp      int16   // 空气入口温度 1C, offset -40
-	CoolantOutTemp byte    // ... Wait header said "冷却水出水口温度探针总数"
-	ProbeCount     uint16
-	ProbeTemps     []byte // 1C, offset -40
+	StackSeq      byte     // 电堆序号
+	Voltage       float32  // 电压 0.1V
+	Current       float32  // 电流 0.1A
+	AirInPressure float32  // 空气入口压力 0.1kPa, offset -100kPa
+	AirInTemp     int16    // 空气入口温度 1C, offset -40
+	ProbeCount    uint16   // 冷却水出水口温度探针总数
+	ProbeTemps    []int16  // 冷却水出水口探针温度 1C, offset -40
+	Raw           RawBytes // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
 }
 
+// ParseFuelCellStackData 解析燃料电池电堆数据 (0x30)
+// 数据截断时返回已解析的电堆与 ParseError
 func ParseFuelCellStackData(data []byte) (*FuelCellStackData, error) {
 	if len(data) < 1 {
-		return nil, errors.New("燃料电池电堆数据长度不足(Header)")
+		return nil, errShort("燃料电池电堆数据长度不足(Header)", 0, 1, 0)
 	}
 	count := data[0]
-	stacks := make([]FuelCellStackInfo, 0, int(count))
+	out := &FuelCellStackData{StackCount: count, Stacks: make([]FuelCellStackInfo, 0, int(count))}
 	offset := 1
 
 	for i := 0; i < int(count); i++ {
 		// Min length check: Seq(1)+Volt(2)+Curr(2)+Pres(2)+Temp(1)+ProbeCount(2) = 10 bytes
 		if len(data) < offset+10 {
-			break
+			return out, errShort("燃料电池电堆数据头不完整", offset, 10, len(data)-offset)
 		}
 
+		start := offset
 		seq := data[offset]
 		volt := binary.BigEndian.Uint16(data[offset+1 : offset+3])
 		curr := binary.BigEndian.Uint16(data[offset+3 : offset+5])
@@ -368,27 +402,38 @@ func ParseFuelCellStackData(data []byte) (*FuelCellStackData, error) {
 
 		needed := int(pCount)
 		available := len(data) - offset
+		readCount := needed
 		if available < needed {
-			needed = available
+			readCount = available
 		}
 
-		pTemps := make([]byte, needed)
-		copy(pTemps, data[offset:offset+needed])
-
-		stacks = append(stacks, FuelCellStackInfo{
-			StackSeq:      seq,
-			Voltage:       float32(volt) * 0.1,
-			Current:       float32(curr) * 0.1,
-			AirInPressure: float32(pres)*0.1 - 100.0,
-			AirInTemp:     int16(temp) - 40,
-			ProbeCount:    pCount,
-			ProbeTemps:    pTemps,
-		})
+		stack := FuelCellStackInfo{
+			StackSeq:   seq,
+			ProbeCount: pCount,
+			Raw:        rawCopy(data[start : offset+readCount]),
+		}
+		stack.ProbeTemps = stack.Validity.temps("ProbeTemps", data[offset:offset+readCount])
+		if stack.Validity.wordOK("Voltage", volt) {
+			stack.Voltage = float32(volt) * 0.1
+		}
+		if stack.Validity.wordOK("Current", curr) {
+			stack.Current = float32(curr) * 0.1
+		}
+		if stack.Validity.wordOK("AirInPressure", pres) {
+			stack.AirInPressure = float32(pres)*0.1 - 100.0
+		}
+		if stack.Validity.byteOK("AirInTemp", temp) {
+			stack.AirInTemp = offsetTemp(temp)
+		}
+		out.Stacks = append(out.Stacks, stack)
+		if readCount < needed {
+			return out, errShort("探针温度数据不完整", offset, needed, available)
+		}
 
-		offset += needed
+		offset += readCount
 	}
 
-	return &FuelCellStackData{StackCount: count, Stacks: stacks}, nil
+	return out, nil
 }
 
 // SuperCapData 超级电容器数据 (类型 0x31)
@@ -401,7 +446,15 @@ type SuperCapData struct {
 	SingleCellCount uint16    // M
 	SingleCellVolts []float32 // 0.01V
 	ProbeCount      uint16    // N
-	ProbeTemps      []byte    // 1C, Offset -40
+	ProbeTemps      []int16   // 1C, Offset -40
+	Raw             RawBytes  // 原始字节
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *SuperCapData) Length() int {
+	return 7 + int(d.SingleCellCount)*2 + 2 + int(d.ProbeCount)
 }
 
 // SuperCapExtremeData 超级电容器极值数据 (类型 0x32)
@@ -418,7 +471,15 @@ type SuperCapExtremeData struct {
 	MaxTempValue     int16 // 1C, Offset -40
 	MinTempSystemNo  byte
 	MinTempProbeCode uint16
-	MinTempValue     int16 // 1C, Offset -40
+	MinTempValue     int16    // 1C, Offset -40
+	Raw              RawBytes // 原始字节 (18 字节)
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *SuperCapExtremeData) Length() int {
+	return 18
 }
 
 // ... (SuperCapExtremeData remains same) ...
@@ -426,7 +487,7 @@ type SuperCapExtremeData struct {
 func ParseSuperCapData(data []byte) (*SuperCapData, error) {
 	// Min Header: Sys(1)+Vol(2)+Cur(2)+CellN(2) = 7
 	if len(data) < 7 {
-		return nil, errors.New("超级电容数据长度不足(Header)")
+		return nil, errShort("超级电容数据长度不足(Header)", 0, 7, len(data))
 	}
 
 	offset := 0
@@ -441,26 +502,19 @@ func ParseSuperCapData(data []byte) (*SuperCapData, error) {
 	cellsBytes := int(cellN) * 2
 	avail := len(data) - offset
 	readCells := cellsBytes
-	realCellN := int(cellN)
 	if avail < cellsBytes {
 		readCells = avail / 2 * 2
-		realCellN = readCells / 2
 	}
+	cellRaw := data[offset : offset+readCells]
+	offset += readCells
 
-	cVolts := make([]float32, 0, realCellN)
-	for j := 0; j < realCellN; j++ {
-		raw := binary.BigEndian.Uint16(data[offset+j*2 : offset+j*2+2])
-		cVolts = append(cVolts, float32(raw)*0.01)
+	if readCells < cellsBytes {
+		return nil, errShort("超级电容单体电压数据不完整", 7, cellsBytes, avail)
 	}
-	offset += readCells
 
 	// Probe Count (2 bytes)
 	if len(data)-offset < 2 {
-		// return partial? or error?
-		// Safe to return what we have?
-		// But ProbeCount is mandatory field.
-		// If missing, error.
-		return nil, errors.New("超级电容数据长度不足(ProbeCnt)")
+		return nil, errShort("超级电容数据长度不足(ProbeCnt)", offset, 2, len(data)-offset)
 	}
 	probeN := binary.BigEndian.Uint16(data[offset : offset+2])
 	offset += 2
@@ -469,30 +523,30 @@ func ParseSuperCapData(data []byte) (*SuperCapData, error) {
 	availP := len(data) - offset
 	readP := int(probeN)
 	if availP < readP {
-		readP = availP
+		return nil, errShort("超级电容探针温度数据不完整", offset, readP, availP)
 	}
 
-	pTemps := make([]byte, readP)
-	copy(pTemps, data[offset:offset+readP])
-	// offset += readP // No need to increment if we return struct.
-	// But to be consistent usually we don't return processedBytes here, the struct implies content.
-	// The `handler` calculates bytes from struct content or we consume exact bytes.
-
-	return &SuperCapData{
+	sc := &SuperCapData{
 		SystemNo:        sysNo,
-		TotalVoltage:    float32(vol) * 0.1,
-		TotalCurrent:    float32(cur)*0.1 - 3000.0,
 		SingleCellCount: cellN,
-		SingleCellVolts: cVolts,
 		ProbeCount:      probeN,
-		ProbeTemps:      pTemps,
-	}, nil
+		Raw:             rawCopy(data[:offset+readP]),
+	}
+	sc.SingleCellVolts = sc.Validity.scaledWords("SingleCellVolts", cellRaw, 0.01)
+	sc.ProbeTemps = sc.Validity.temps("ProbeTemps", data[offset:offset+readP])
+	if sc.Validity.wordOK("TotalVoltage", vol) {
+		sc.TotalVoltage = float32(vol) * 0.1
+	}
+	if sc.Validity.wordOK("TotalCurrent", cur) {
+		sc.TotalCurrent = float32(cur)*0.1 - 3000.0
+	}
+	return sc, nil
 }
 
 func ParseSuperCapExtremeData(data []byte) (*SuperCapExtremeData, error) {
 	// Length check: 1+2+2 + 1+2+2 + 1+2+1 + 1+2+1 = 18 bytes
 	if len(data) < 18 {
-		return nil, errors.New("超级电容极值数据长度不足")
+		return nil, errShort("超级电容极值数据长度不足", 0, 18, len(data))
 	}
 
 	// Max V
@@ -515,18 +569,28 @@ func ParseSuperCapExtremeData(data []byte) (*SuperCapExtremeData, error) {
 	minTCode := binary.BigEndian.Uint16(data[15:17])
 	minTVal := data[17]
 
-	return &SuperCapExtremeData{
+	x := &SuperCapExtremeData{
 		MaxVoltSystemNo:  maxVSys,
 		MaxVoltCellCode:  maxVCode,
-		MaxVoltValue:     float32(maxVVal) * 0.001,
 		MinVoltSystemNo:  minVSys,
 		MinVoltCellCode:  minVCode,
-		MinVoltValue:     float32(minVVal) * 0.001,
 		MaxTempSystemNo:  maxTSys,
 		MaxTempProbeCode: maxTCode,
-		MaxTempValue:     int16(maxTVal) - 40,
 		MinTempSystemNo:  minTSys,
 		MinTempProbeCode: minTCode,
-		MinTempValue:     int16(minTVal) - 40,
-	}, nil
+		Raw:              rawCopy(data[:18]),
+	}
+	if x.Validity.wordOK("MaxVoltValue", maxVVal) {
+		x.MaxVoltValue = float32(maxVVal) * 0.001
+	}
+	if x.Validity.wordOK("MinVoltValue", minVVal) {
+		x.MinVoltValue = float32(minVVal) * 0.001
+	}
+	if x.Validity.byteOK("MaxTempValue", maxTVal) {
+		x.MaxTempValue = offsetTemp(maxTVal)
+	}
+	if x.Validity.byteOK("MinTempValue", minTVal) {
+		x.MinTempValue = offsetTemp(minTVal)
+	}
+	return x, nil
 }
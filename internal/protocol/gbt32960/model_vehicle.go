@@ -2,30 +2,37 @@ package gbt32960
 
 import (
 	"encoding/binary"
-	"errors"
 )
 
 // VehicleData 整车数据 (类型 0x01)
 type VehicleData struct {
-	Status        byte    // 车辆状态 (0x01:启动, 0x02:熄火, 0x03:其他)
-	ChargeStatus  byte    // 充电状态 (0x01:停车充电, 0x02:行驶充电, 0x03:未充电, 0x04:充电完成)
-	RunMode       byte    // 运行模式 (0x01:纯电, 0x02:混动, 0x03:燃油)
-	Speed         float32 // 车速 (km/h) 精度0.1
-	TotalMileage  float64 // 累计里程 (km) 精度0.1
-	Voltage       float32 // 总电压 (V) 精度0.1
-	Current       float32 // 总电流 (A) 精度0.1, 偏移量1000A (0=-1000A)
-	SOC           byte    // SOC (%)
-	DCStatus      byte    // DC-DC状态 (0x01:工作, 0x02:断开)
-	Gear          byte    // 挡位 (位掩码)
-	InsulationRes uint16  // 绝缘电阻 (kΩ)
-	AccelPedal    byte    // 加速踏板行程值 (%) (0-100)
-	BrakePedal    byte    // 制动踏板行程值 (%) (0-100)
+	Status        byte     // 车辆状态 (0x01:启动, 0x02:熄火, 0x03:其他)
+	ChargeStatus  byte     // 充电状态 (0x01:停车充电, 0x02:行驶充电, 0x03:未充电, 0x04:充电完成)
+	RunMode       byte     // 运行模式 (0x01:纯电, 0x02:混动, 0x03:燃油)
+	Speed         float32  // 车速 (km/h) 精度0.1
+	TotalMileage  float64  // 累计里程 (km) 精度0.1
+	Voltage       float32  // 总电压 (V) 精度0.1
+	Current       float32  // 总电流 (A) 精度0.1, 偏移量1000A (0=-1000A)
+	SOC           byte     // SOC (%)
+	DCStatus      byte     // DC-DC状态 (0x01:工作, 0x02:断开)
+	Gear          byte     // 挡位 (位掩码)
+	InsulationRes uint16   // 绝缘电阻 (kΩ)
+	AccelPedal    byte     // 加速踏板行程值 (%) (0-100)
+	BrakePedal    byte     // 制动踏板行程值 (%) (0-100)
+	Raw           RawBytes // 原始字节 (20 字节)
+
+	Validity FieldStatus `json:",omitempty"` // 异常/无效字段
+}
+
+// Length 数据块在报文中占用的字节数 (不含信息类型标志)
+func (d *VehicleData) Length() int {
+	return 20
 }
 
 // ParseVehicleData 解析整车数据 (20字节)
 func ParseVehicleData(data []byte) (*VehicleData, error) {
 	if len(data) < 20 {
-		return nil, errors.New("整车数据长度不足")
+		return nil, errShort("整车数据长度不足", 0, 20, len(data))
 	}
 
 	speedRaw := binary.BigEndian.Uint16(data[3:5])
@@ -34,21 +41,43 @@ func ParseVehicleData(data []byte) (*VehicleData, error) {
 	currRaw := binary.BigEndian.Uint16(data[11:13])
 	insRes := binary.BigEndian.Uint16(data[16:18])
 
-	return &VehicleData{
-		Status:       data[0],
-		ChargeStatus: data[1],
-		RunMode:      data[2],
-		Speed:        float32(speedRaw) / 10.0,
-		TotalMileage: float64(mileageRaw) / 10.0,
-		Voltage:      float32(voltRaw) / 10.0,
-		// 电流偏移量 1000A。即 0 表示 -1000A，10000(0x2710) 表示 0A。
-		// 公式: 实值 = (原始值 * 0.1) - 1000.0
-		Current:       (float32(currRaw) * 0.1) - 1000.0,
+	v := &VehicleData{
+		Status:        data[0],
+		ChargeStatus:  data[1],
+		RunMode:       data[2],
 		SOC:           data[13],
 		DCStatus:      data[14],
 		Gear:          data[15],
 		InsulationRes: insRes,
 		AccelPedal:    data[18],
 		BrakePedal:    data[19],
-	}, nil
+		Raw:           rawCopy(data[:20]),
+	}
+
+	// 状态类字段保留原始标记值，只记录有效性
+	v.Validity.byteOK("Status", data[0])
+	v.Validity.byteOK("ChargeStatus", data[1])
+	v.Validity.byteOK("RunMode", data[2])
+	v.Validity.byteOK("SOC", data[13])
+	v.Validity.byteOK("DCStatus", data[14])
+	v.Validity.wordOK("InsulationRes", insRes)
+	v.Validity.byteOK("AccelPedal", data[18])
+	v.Validity.byteOK("BrakePedal", data[19])
+
+	if v.Validity.wordOK("Speed", speedRaw) {
+		v.Speed = float32(speedRaw) / 10.0
+	}
+	if v.Validity.dwordOK("TotalMileage", mileageRaw) {
+		v.TotalMileage = float64(mileageRaw) / 10.0
+	}
+	if v.Validity.wordOK("Voltage", voltRaw) {
+		v.Voltage = float32(voltRaw) / 10.0
+	}
+	if v.Validity.wordOK("Current", currRaw) {
+		// 电流偏移量 1000A。即 0 表示 -1000A，10000(0x2710) 表示 0A。
+		// 公式: 实值 = (原始值 * 0.1) - 1000.0
+		v.Current = (float32(currRaw) * 0.1) - 1000.0
+	}
+
+	return v, nil
 }
@@ -4,22 +4,30 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // GB/T 32960.3-2025 协议常量定义
 const (
-	StartChar = 0x2323 // 起始符 "##"
+	StartChar     = 0x2323 // 起始符 "##"
+	StartChar2025 = 0x2424 // 起始符 "$$" (GB/T 32960.3-2025)
 	// HeaderLength: 2(Start) + 1(Cmd) + 1(Resp) + 17(VIN) + 1(Enc) + 2(Len) = 24
 	HeaderLength = 24
 	// MinPacketSize: Header + Checksum(1) = 25
 	MinPacketSize = 25
 
 	// 命令标识
-	CmdVehicleLogin  = 0x01 // 车辆登入
-	CmdPlatformLogin = 0x05 // 平台登入
-	CmdRealTime      = 0x02
-	CmdLogout        = 0x03
-	CmdHeartbeat     = 0x07
+	CmdVehicleLogin    = 0x01 // 车辆登入
+	CmdPlatformLogin   = 0x05 // 平台登入
+	CmdRealTime        = 0x02
+	CmdLogout          = 0x03
+	CmdReissue         = 0x04 // 补发信息上报
+	CmdPlatformLogout  = 0x06 // 平台登出
+	CmdHeartbeat       = 0x07
+	CmdTimeCalibration = 0x08 // 终端校时
+	CmdQueryParams     = 0x80 // 参数查询 (平台下行)
+	CmdSetParams       = 0x81 // 参数设置 (平台下行)
+	CmdTerminalControl = 0x82 // 车载终端控制 (平台下行)
 )
 
 type ProtocolVersion int
@@ -29,6 +37,27 @@ const (
 	Version2025                        // 0x24 0x24 ($$)
 )
 
+// startByte 返回协议版本对应的起始符字节 (起始符由两个相同字节组成)
+// 两个版本的报文头其余字段布局一致，仅起始符不同
+func startByte(v ProtocolVersion) byte {
+	if v == Version2025 {
+		return 0x24
+	}
+	return 0x23
+}
+
+// versionOf 根据起始符识别协议版本
+func versionOf(b0, b1 byte) (ProtocolVersion, bool) {
+	switch {
+	case b0 == 0x23 && b1 == 0x23:
+		return Version2016, true
+	case b0 == 0x24 && b1 == 0x24:
+		return Version2025, true
+	default:
+		return Version2016, false
+	}
+}
+
 // Packet 代表一个解析后的 GB/T 32960 报文
 type Packet struct {
 	Version      ProtocolVersion // 协议版本
@@ -44,18 +73,14 @@ type Packet struct {
 
 // ParseHeader 尝试从字节切片开头解析报文头。
 // 返回预期的数据单元长度或错误。
-// 假设切片以 "##" 开头。
+// 切片应以 "##" (2016) 或 "$$" (2025) 开头。
 func ParseHeader(data []byte) (dataLen uint16, err error) {
 	if len(data) < HeaderLength { // 24
 		return 0, errors.New("数据长度不足以解析头部")
 	}
 
 	// 检查起始符
-	if data[0] == 0x23 && data[1] == 0x23 {
-		// Version 2016
-	} else if data[0] == 0x24 && data[1] == 0x24 {
-		// Version 2025
-	} else {
+	if _, ok := versionOf(data[0], data[1]); !ok {
 		return 0, fmt.Errorf("无效的起始符: %X%X", data[0], data[1])
 	}
 
@@ -70,6 +95,34 @@ func ParseHeader(data []byte) (dataLen uint16, err error) {
 	return dataLen, nil
 }
 
+// DecodePacket 将一帧完整报文解析为 Packet 结构体
+// 校验起始符、数据单元长度与 BCC 校验码，数据单元为独立副本
+func DecodePacket(frame []byte) (*Packet, error) {
+	dataLen, err := ParseHeader(frame)
+	if err != nil {
+		return nil, err
+	}
+	if len(frame) != HeaderLength+int(dataLen)+1 {
+		return nil, fmt.Errorf("报文长度 %d 与数据单元长度 %d 不符", len(frame), dataLen)
+	}
+	if !VerifyChecksum(frame) {
+		return nil, errors.New("校验码错误")
+	}
+
+	version, _ := versionOf(frame[0], frame[1])
+	dataUnit := make([]byte, dataLen)
+	copy(dataUnit, frame[HeaderLength:HeaderLength+int(dataLen)])
+
+	return &Packet{
+		Version:    version,
+		Command:    frame[2],
+		Response:   frame[3],
+		VIN:        strings.TrimRight(string(frame[4:21]), "\x00 "),
+		Encryption: frame[21],
+		DataUnit:   dataUnit,
+	}, nil
+}
+
 // VerifyChecksum 验证完整报文 packetData 的 BCC 校验码。
 // Checksum Range: From Command (Index 2) to Data End.
 func VerifyChecksum(packetData []byte) bool {
@@ -5,6 +5,7 @@ import (
 )
 
 // EncodePacket 将 Packet 结构体编码为字节流
+// 起始符按 Packet.Version 写入: 2016 为 "##"，2025 为 "$$"
 func EncodePacket(pkt *Packet) []byte {
 	// Structure: [Start 2][Cmd 1][Resp 1][VIN 17][Enc 1][Len 2][Data N][Check 1]
 	// Header = 24 bytes
@@ -12,9 +13,9 @@ func EncodePacket(pkt *Packet) []byte {
 	totalLen := 2 + 1 + 1 + 17 + 1 + 2 + dataLen + 1
 	buf := make([]byte, totalLen)
 
-	// 1. Start ##
-	buf[0] = 0x23
-	buf[1] = 0x23
+	// 1. Start ## / $$
+	buf[0] = startByte(pkt.Version)
+	buf[1] = buf[0]
 
 	// 2. Cmd
 	buf[2] = pkt.Command
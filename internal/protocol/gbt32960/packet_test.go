@@ -0,0 +1,59 @@
+package gbt32960
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeDecodePacketRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		version ProtocolVersion
+		start   string
+	}{
+		{"2016", Version2016, "##"},
+		{"2025", Version2025, "$$"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := &Packet{
+				Version:    tt.version,
+				Command:    CmdRealTime,
+				Response:   0xFE,
+				VIN:        "LSVAU2180N2183294",
+				Encryption: 0x01,
+				DataUnit:   []byte{0x1A, 0x0A, 0x10, 0x08, 0x1E, 0x00, 0x01, 0x02, 0x03},
+			}
+
+			frame := EncodePacket(want)
+			if got := string(frame[:2]); got != tt.start {
+				t.Fatalf("起始符 = %q, 期望 %q", got, tt.start)
+			}
+
+			got, err := DecodePacket(frame)
+			if err != nil {
+				t.Fatalf("DecodePacket: %v", err)
+			}
+			if got.Version != want.Version || got.Command != want.Command || got.Response != want.Response ||
+				got.VIN != want.VIN || got.Encryption != want.Encryption {
+				t.Errorf("报文头 = %+v, 期望 %+v", got, want)
+			}
+			if !bytes.Equal(got.DataUnit, want.DataUnit) {
+				t.Errorf("数据单元 = %x, 期望 %x", got.DataUnit, want.DataUnit)
+			}
+			if again := EncodePacket(got); !bytes.Equal(again, frame) {
+				t.Errorf("重新编码 = %x, 期望 %x", again, frame)
+			}
+		})
+	}
+}
+
+func TestDecodePacketRejectsBadChecksum(t *testing.T) {
+	for _, v := range []ProtocolVersion{Version2016, Version2025} {
+		frame := EncodePacket(&Packet{Version: v, Command: CmdHeartbeat, VIN: "LSVAU2180N2183294", Encryption: 0x01})
+		frame[len(frame)-1] ^= 0xFF
+		if _, err := DecodePacket(frame); err == nil {
+			t.Errorf("版本 %d: 校验码错误的报文应解析失败", v)
+		}
+	}
+}
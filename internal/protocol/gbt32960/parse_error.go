@@ -0,0 +1,42 @@
+package gbt32960
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ParseError 信息单元解析错误
+// 解析函数返回的 Offset 相对信息体起点, 由调用方通过 Locate 换算为数据单元内的偏移并填写信息类型
+// 批量数据 (多个子系统/电池包) 在截断时同时返回已解析的部分数据与 ParseError
+type ParseError struct {
+	InfoType byte   // 信息类型标志
+	Offset   int    // 出错位置的偏移
+	Expected int    // 期望字节数 (0 表示非长度错误)
+	Actual   int    // 实际可用字节数
+	Reason   string // 错误说明
+}
+
+func (e *ParseError) Error() string {
+	if e.Expected == 0 {
+		return fmt.Sprintf("信息类型 0x%02X 解析失败 (偏移 %d): %s", e.InfoType, e.Offset, e.Reason)
+	}
+	return fmt.Sprintf("信息类型 0x%02X 解析失败 (偏移 %d): %s, 期望 %d 字节, 实际 %d 字节",
+		e.InfoType, e.Offset, e.Reason, e.Expected, e.Actual)
+}
+
+// Locate 将任意解析错误转换为 ParseError，base 为信息体在数据单元中的偏移
+func Locate(err error, infoType byte, base int) *ParseError {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		out := *pe
+		out.InfoType = infoType
+		out.Offset += base
+		return &out
+	}
+	return &ParseError{InfoType: infoType, Offset: base, Reason: err.Error()}
+}
+
+// errShort 长度不足，offset 为出错位置相对信息体起点的偏移
+func errShort(reason string, offset, expected, actual int) *ParseError {
+	return &ParseError{Offset: offset, Expected: expected, Actual: actual, Reason: reason}
+}
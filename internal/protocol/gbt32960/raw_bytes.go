@@ -0,0 +1,29 @@
+package gbt32960
+
+import (
+	"encoding/hex"
+	"encoding/json"
+)
+
+// RawBytes 数据块的原始字节 (用于审计换算结果)，JSON 编码为十六进制字符串
+type RawBytes []byte
+
+// MarshalJSON 编码为十六进制字符串
+func (r RawBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(r))
+}
+
+// String 十六进制表示
+func (r RawBytes) String() string {
+	return hex.EncodeToString(r)
+}
+
+// rawCopy 复制原始字节，避免引用报文缓冲区
+func rawCopy(b []byte) RawBytes {
+	return append(RawBytes(nil), b...)
+}
+
+// offsetTemp 将偏移 40℃ 编码的温度转换为有符号摄氏度 (调用方须先检查异常/无效标记)
+func offsetTemp(raw byte) int16 {
+	return int16(raw) - 40
+}
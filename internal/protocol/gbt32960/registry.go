@@ -0,0 +1,92 @@
+package gbt32960
+
+import (
+	"fmt"
+	"sync"
+)
+
+// InfoDecoder 信息单元解码函数，返回解析结果与占用的字节数 (不含信息类型标志)
+// 出错时可同时返回已解析的部分数据, 无可用数据时返回 nil
+type InfoDecoder func(body []byte) (value interface{}, consumed int, err error)
+
+// InfoUnit 已注册的信息类型
+type InfoUnit struct {
+	MsgType string // 分发消息类型
+	Decode  InfoDecoder
+}
+
+type registryKey struct {
+	version  ProtocolVersion
+	infoType byte
+}
+
+// Registry 按 (协议版本, 信息类型) 注册的实时数据解码器
+type Registry struct {
+	mu    sync.RWMutex
+	units map[registryKey]InfoUnit
+}
+
+// NewRegistry 创建一个空的解码器注册表
+func NewRegistry() *Registry {
+	return &Registry{units: make(map[registryKey]InfoUnit)}
+}
+
+// NewStandardRegistry 创建已注册 GB/T 32960 标准信息类型的解码器注册表
+func NewStandardRegistry() *Registry {
+	r := NewRegistry()
+	for _, v := range []ProtocolVersion{Version2016, Version2025} {
+		r.Register(v, byte(DataTypeVehicle), "VEHICLE", decoderOf(ParseVehicleData))
+		r.Register(v, byte(DataTypeMotor), "MOTOR", decoderOf(ParseMotorData))
+		r.Register(v, byte(DataTypeFuelCell), "FUEL_CELL", decoderOf(ParseFuelCellData))
+		r.Register(v, byte(DataTypeEngine), "ENGINE", decoderOf(ParseEngineData))
+		r.Register(v, byte(DataTypeLocation), "LOCATION", decoderOf(ParseLocationData))
+		r.Register(v, byte(DataTypeFuelCellStack), "FUEL_CELL_STACK", decoderOf(ParseFuelCellStackData))
+		r.Register(v, byte(DataTypeSuperCap), "SUPER_CAP", decoderOf(ParseSuperCapData))
+		r.Register(v, byte(DataTypeSuperCapExtreme), "SUPER_CAP_EXTREME", decoderOf(ParseSuperCapExtremeData))
+	}
+
+	// 2016 版
+	r.Register(Version2016, 0x06, "EXTREME", decoderOf(ParseExtremeData))
+	r.Register(Version2016, 0x07, "ALARM", decoderOf(ParseAlarmData2016))
+	r.Register(Version2016, 0x08, "STORAGE_VOLTAGE", decoderOf(ParseStorageVoltageData2016))
+	r.Register(Version2016, 0x09, "STORAGE_TEMP", decoderOf(ParseStorageTempData2016))
+
+	// 2025 版 (0x09 为自定义数据)
+	r.Register(Version2025, 0x06, "ALARM", decoderOf(ParseAlarmData2025))
+	r.Register(Version2025, 0x07, "BATTERY_VOLTAGE", decoderOf(ParseBatteryVoltageData2025))
+	r.Register(Version2025, 0x08, "BATTERY_TEMP", decoderOf(ParseBatteryTempData2025))
+	return r
+}
+
+// Register 注册解码器，覆盖同一 (协议版本, 信息类型) 已有的注册
+func (r *Registry) Register(version ProtocolVersion, infoType byte, msgType string, decode InfoDecoder) {
+	if msgType == "" {
+		msgType = fmt.Sprintf("INFO_%02X", infoType)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.units[registryKey{version: version, infoType: infoType}] = InfoUnit{MsgType: msgType, Decode: decode}
+}
+
+// Lookup 查找解码器
+func (r *Registry) Lookup(version ProtocolVersion, infoType byte) (InfoUnit, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	u, ok := r.units[registryKey{version: version, infoType: infoType}]
+	return u, ok
+}
+
+// decoderOf 将 ParseXxx 函数包装为 InfoDecoder，占用长度取自解析结果的 Length 方法
+func decoderOf[T any, P interface {
+	*T
+	Length() int
+}](parse func([]byte) (P, error)) InfoDecoder {
+	return func(body []byte) (interface{}, int, error) {
+		v, err := parse(body)
+		if v == nil {
+			// 避免将 nil 指针作为非 nil 接口返回
+			return nil, 0, err
+		}
+		return v, v.Length(), err
+	}
+}
@@ -43,18 +43,37 @@ func BuildGeneralResponse(requestTime []byte) []byte {
 	if len(requestTime) >= 6 {
 		copy(respData, requestTime[:6])
 	} else {
-		now := time.Now()
-		respData[0] = byte(now.Year() - 2000)
-		respData[1] = byte(now.Month())
-		respData[2] = byte(now.Day())
-		respData[3] = byte(now.Hour())
-		respData[4] = byte(now.Minute())
-		respData[5] = byte(now.Second())
+		copy(respData, EncodeTime(time.Now()))
 	}
 	return respData
 }
 
+// BuildTimeCalibrationResponse 构建终端校时应答 (0x08)
+// 响应格式: [Time 6] 平台当前时间
+func BuildTimeCalibrationResponse(now time.Time) []byte {
+	return EncodeTime(now)
+}
+
+// EncodeTime 将时间编码为 6 字节 [年 月 日 时 分 秒] (GMT+8, 年份为 2000 年起的偏移)
+func EncodeTime(t time.Time) []byte {
+	t = t.In(collectTimeLocation)
+	return []byte{
+		byte(t.Year() - 2000),
+		byte(t.Month()),
+		byte(t.Day()),
+		byte(t.Hour()),
+		byte(t.Minute()),
+		byte(t.Second()),
+	}
+}
+
 // BuildLogoutResponse 构建登出应答 (复用车辆登入应答格式 [Time 6][Seq 2][Result 1])
 func BuildLogoutResponse(vin string, success bool, requestTime []byte) []byte {
 	return BuildVehicleLoginResponse(vin, success, requestTime)
 }
+
+// BuildHeartbeatResponse 构建心跳应答 (0x07)
+// 应答数据单元为空，结果由 Header 中的 Response Flag 表示
+func BuildHeartbeatResponse() []byte {
+	return []byte{}
+}
@@ -0,0 +1,140 @@
+package gbt32960
+
+// 物理量单位
+const (
+	UnitVolt        = "V"
+	UnitAmpere      = "A"
+	UnitCelsius     = "℃"
+	UnitKmPerHour   = "km/h"
+	UnitKm          = "km"
+	UnitRPM         = "r/min"
+	UnitNewtonMeter = "N·m"
+	UnitKiloOhm     = "kΩ"
+	UnitPercent     = "%"
+	UnitDegree      = "°"
+	UnitKPa         = "kPa"
+	UnitMPa         = "MPa"
+	UnitMgPerKg     = "mg/kg"
+	UnitKgPer100Km  = "kg/100km"
+	UnitLPer100Km   = "L/100km"
+)
+
+// 各数据模型的物理量单位，键为 JSON 字段路径 (数组成员以 "." 连接, 如 MotorList.Speed)
+// 随消息以 units 字段发布
+var (
+	vehicleUnits = map[string]string{
+		"Speed":         UnitKmPerHour,
+		"TotalMileage":  UnitKm,
+		"Voltage":       UnitVolt,
+		"Current":       UnitAmpere,
+		"SOC":           UnitPercent,
+		"InsulationRes": UnitKiloOhm,
+		"AccelPedal":    UnitPercent,
+		"BrakePedal":    UnitPercent,
+	}
+	motorUnits = map[string]string{
+		"MotorList.CtrlTemp": UnitCelsius,
+		"MotorList.Speed":    UnitRPM,
+		"MotorList.Torque":   UnitNewtonMeter,
+		"MotorList.Temp":     UnitCelsius,
+		"MotorList.Voltage":  UnitVolt,
+		"MotorList.Current":  UnitAmpere,
+	}
+	fuelCellUnits = map[string]string{
+		"Voltage":         UnitVolt,
+		"Current":         UnitAmpere,
+		"FuelConsumeRate": UnitKgPer100Km,
+		"ProbeTemps":      UnitCelsius,
+		"MaxTemp":         UnitCelsius,
+		"MaxH2Conc":       UnitMgPerKg,
+		"MaxH2Pressure":   UnitMPa,
+	}
+	engineUnits = map[string]string{
+		"Speed":    UnitRPM,
+		"FuelRate": UnitLPer100Km,
+	}
+	locationUnits = map[string]string{
+		"Longitude": UnitDegree,
+		"Latitude":  UnitDegree,
+	}
+	extremeUnits = map[string]string{
+		"MaxVoltage": UnitVolt,
+		"MinVoltage": UnitVolt,
+		"MaxTemp":    UnitCelsius,
+		"MinTemp":    UnitCelsius,
+	}
+	storageVoltageUnits = map[string]string{
+		"Subsystems.Voltage":      UnitVolt,
+		"Subsystems.Current":      UnitAmpere,
+		"Subsystems.CellVoltages": UnitVolt,
+	}
+	batteryVoltageUnits = map[string]string{
+		"PackVoltages.Voltage":           UnitVolt,
+		"PackVoltages.Current":           UnitAmpere,
+		"PackVoltages.FrameCellVoltages": UnitVolt,
+	}
+	storageTempUnits = map[string]string{
+		"Subsystems.Temperatures": UnitCelsius,
+	}
+	batteryTempUnits = map[string]string{
+		"PackTemps.ProbeTemps": UnitCelsius,
+	}
+	fuelCellStackUnits = map[string]string{
+		"Stacks.Voltage":       UnitVolt,
+		"Stacks.Current":       UnitAmpere,
+		"Stacks.AirInPressure": UnitKPa,
+		"Stacks.AirInTemp":     UnitCelsius,
+		"Stacks.ProbeTemps":    UnitCelsius,
+	}
+	superCapUnits = map[string]string{
+		"TotalVoltage":    UnitVolt,
+		"TotalCurrent":    UnitAmpere,
+		"SingleCellVolts": UnitVolt,
+		"ProbeTemps":      UnitCelsius,
+	}
+	superCapExtremeUnits = map[string]string{
+		"MaxVoltValue": UnitVolt,
+		"MinVoltValue": UnitVolt,
+		"MaxTempValue": UnitCelsius,
+		"MinTempValue": UnitCelsius,
+	}
+)
+
+// Units 物理量单位
+func (d *VehicleData) Units() map[string]string { return vehicleUnits }
+
+// Units 物理量单位
+func (d *MotorData) Units() map[string]string { return motorUnits }
+
+// Units 物理量单位
+func (d *FuelCellData) Units() map[string]string { return fuelCellUnits }
+
+// Units 物理量单位
+func (d *EngineData) Units() map[string]string { return engineUnits }
+
+// Units 物理量单位
+func (d *LocationData) Units() map[string]string { return locationUnits }
+
+// Units 物理量单位
+func (d *ExtremeData) Units() map[string]string { return extremeUnits }
+
+// Units 物理量单位
+func (d *StorageVoltageData2016) Units() map[string]string { return storageVoltageUnits }
+
+// Units 物理量单位
+func (d *BatteryVoltageData) Units() map[string]string { return batteryVoltageUnits }
+
+// Units 物理量单位
+func (d *StorageTempData2016) Units() map[string]string { return storageTempUnits }
+
+// Units 物理量单位
+func (d *BatteryTempData) Units() map[string]string { return batteryTempUnits }
+
+// Units 物理量单位
+func (d *FuelCellStackData) Units() map[string]string { return fuelCellStackUnits }
+
+// Units 物理量单位
+func (d *SuperCapData) Units() map[string]string { return superCapUnits }
+
+// Units 物理量单位
+func (d *SuperCapExtremeData) Units() map[string]string { return superCapExtremeUnits }
@@ -0,0 +1,110 @@
+package gbt32960
+
+import (
+	"encoding/binary"
+	"strconv"
+)
+
+// FieldState 字段有效性状态
+// GB/T 32960 以 0xFE / 0xFFFE / 0xFFFFFFFE 表示异常, 0xFF / 0xFFFF / 0xFFFFFFFF 表示无效
+type FieldState string
+
+const (
+	FieldAbnormal FieldState = "ABNORMAL" // 异常
+	FieldInvalid  FieldState = "INVALID"  // 无效
+)
+
+// FieldStatus 记录模型中异常或无效的字段 (键为字段名, 数组元素为 "字段名[下标]")，未记录的字段均有效
+// 被记录字段的物理值不做换算 (保持零值)，发布到 MQ 时输出为 null
+type FieldStatus map[string]FieldState
+
+// byteOK 检查 BYTE 字段的异常/无效标记，有效时返回 true
+func (s *FieldStatus) byteOK(name string, raw byte) bool {
+	return s.check(name, raw == 0xFE, raw == 0xFF)
+}
+
+// wordOK 检查 WORD 字段的异常/无效标记，有效时返回 true
+func (s *FieldStatus) wordOK(name string, raw uint16) bool {
+	return s.check(name, raw == 0xFFFE, raw == 0xFFFF)
+}
+
+// dwordOK 检查 DWORD 字段的异常/无效标记，有效时返回 true
+func (s *FieldStatus) dwordOK(name string, raw uint32) bool {
+	return s.check(name, raw == 0xFFFFFFFE, raw == 0xFFFFFFFF)
+}
+
+// indexed 数组元素在 FieldStatus 中的键, 如 CellVoltages[3]
+func indexed(name string, i int) string {
+	return name + "[" + strconv.Itoa(i) + "]"
+}
+
+// scaledWords 按精度换算 WORD 数组 (如单体电压)，异常/无效的元素记录为 name[i] 并保持 0
+func (s *FieldStatus) scaledWords(name string, raw []byte, precision float32) []float32 {
+	values := make([]float32, len(raw)/2)
+	for i := range values {
+		v := binary.BigEndian.Uint16(raw[i*2 : i*2+2])
+		if s.wordOK(indexed(name, i), v) {
+			values[i] = float32(v) * precision
+		}
+	}
+	return values
+}
+
+// temps 批量转换偏移 40℃ 编码的探针温度，异常/无效的探针记录为 name[i] 并保持 0
+func (s *FieldStatus) temps(name string, raw []byte) []int16 {
+	temps := make([]int16, len(raw))
+	for i, b := range raw {
+		if s.byteOK(indexed(name, i), b) {
+			temps[i] = offsetTemp(b)
+		}
+	}
+	return temps
+}
+
+func (s *FieldStatus) check(name string, abnormal, invalid bool) bool {
+	if !abnormal && !invalid {
+		return true
+	}
+	if *s == nil {
+		*s = make(FieldStatus)
+	}
+	if abnormal {
+		(*s)[name] = FieldAbnormal
+	} else {
+		(*s)[name] = FieldInvalid
+	}
+	return false
+}
+
+// markByte 编码 BYTE 字段: 记录为异常/无效的字段写出 0xFE / 0xFF，否则写出原始值
+func (s FieldStatus) markByte(name string, raw byte) byte {
+	switch s[name] {
+	case FieldAbnormal:
+		return 0xFE
+	case FieldInvalid:
+		return 0xFF
+	}
+	return raw
+}
+
+// markWord 编码 WORD 字段的异常/无效标记
+func (s FieldStatus) markWord(name string, raw uint16) uint16 {
+	switch s[name] {
+	case FieldAbnormal:
+		return 0xFFFE
+	case FieldInvalid:
+		return 0xFFFF
+	}
+	return raw
+}
+
+// markDword 编码 DWORD 字段的异常/无效标记
+func (s FieldStatus) markDword(name string, raw uint32) uint32 {
+	switch s[name] {
+	case FieldAbnormal:
+		return 0xFFFFFFFE
+	case FieldInvalid:
+		return 0xFFFFFFFF
+	}
+	return raw
+}
@@ -0,0 +1,56 @@
+package gbt32960
+
+import "errors"
+
+// VIN 校验错误 (ISO 3779 / GB 16735)
+var (
+	ErrVINLength     = errors.New("VIN 长度不是 17 位")
+	ErrVINCharset    = errors.New("VIN 含非法字符 (仅允许 0-9 与除 I/O/Q 外的大写字母)")
+	ErrVINCheckDigit = errors.New("VIN 第 9 位校验码错误")
+)
+
+// vinWeights 各位置加权系数, 第 9 位为校验位
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// ValidateVIN 校验 VIN 的长度、字符集与第 9 位校验码
+func ValidateVIN(vin string) error {
+	if len(vin) != 17 {
+		return ErrVINLength
+	}
+	sum := 0
+	for i := 0; i < 17; i++ {
+		v, ok := vinValue(vin[i])
+		if !ok {
+			return ErrVINCharset
+		}
+		sum += v * vinWeights[i]
+	}
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	if vin[8] != check {
+		return ErrVINCheckDigit
+	}
+	return nil
+}
+
+// vinValue 字符对应值 (字母按 ISO 3779 对照表转换)
+func vinValue(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	default:
+		return 0, false
+	}
+}
@@ -3,7 +3,6 @@ package server
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/panjf2000/gnet/v2"
 
@@ -38,6 +37,11 @@ func (w *GnetConnWrapper) Write(b []byte) (n int, err error) {
 	return w.conn.Write(b)
 }
 
+// AsyncWrite 经 gnet AsyncWrite 交由连接所在的事件循环发送，可在任意 goroutine 中调用
+func (w *GnetConnWrapper) AsyncWrite(b []byte) error {
+	return w.conn.AsyncWrite(b, nil)
+}
+
 func (w *GnetConnWrapper) SetPlatformAuthenticated(v bool) {
 	ctx, ok := w.conn.Context().(*connContext)
 	if ok {
@@ -113,12 +117,8 @@ func (s *TCPServer) OnTraffic(c gnet.Conn) (action gnet.Action) {
 			}
 
 			if token != nil {
-				// 获取到有效的报文数据
-				// 我们需要在这里解析头部或直接转换字节为 Packet 结构体
-				// SplitFunc 返回的是原始字节流。
-				// 我们必须手动构建 Packet 结构体或使用辅助函数。
-
-				pkt, err := parseRawPacket(token)
+				// 获取到有效的报文数据，解析为 Packet 结构体 (保留起始符对应的协议版本)
+				pkt, err := protocol.DecodePacket(token)
 				if err != nil {
 					s.logger.Warn("Failed to parse packet struct", zap.Error(err))
 				} else {
@@ -142,6 +142,7 @@ func (s *TCPServer) OnTraffic(c gnet.Conn) (action gnet.Action) {
 
 func (s *TCPServer) OnClose(c gnet.Conn, err error) (action gnet.Action) {
 	s.logger.Info("Connection closed", zap.String("remote", c.RemoteAddr().String()), zap.Error(err))
+	s.handler.HandleDisconnect(c.RemoteAddr().String())
 	return
 }
 
@@ -163,40 +164,3 @@ func (s *TCPServer) Stop(ctx context.Context) error {
 	s.logger.Info("Stopping TCP Server...")
 	return gnet.Stop(context.Background(), s.addr)
 }
-
-// parseRawPacket 将原始有效帧字节转换为 Packet 结构体
-func parseRawPacket(data []byte) (*protocol.Packet, error) {
-	if len(data) < 25 { // Header(24) + Checksum(1) = 25 Min
-		return nil, fmt.Errorf("packet too short")
-	}
-
-	cmd := data[2]
-	resp := data[3]
-	vin := strings.TrimRight(string(data[4:21]), "\x00 ")
-	enc := data[21]
-
-	dataUnit := data[24 : len(data)-1]
-
-	// 创建 DataUnit 副本以防万一
-	duCopy := make([]byte, len(dataUnit))
-	copy(duCopy, dataUnit)
-
-	var ver protocol.ProtocolVersion
-	if data[0] == 0x23 && data[1] == 0x23 {
-		ver = protocol.Version2016
-	} else if data[0] == 0x24 && data[1] == 0x24 {
-		ver = protocol.Version2025
-	} else {
-		ver = protocol.Version2016
-	}
-
-	return &protocol.Packet{
-		Version:      ver,
-		Command:      cmd,
-		Response:     resp,
-		VIN:          vin,
-		Encryption:   enc,
-		DataUnit:     duCopy,
-		OriginalData: nil,
-	}, nil
-}
@@ -72,7 +72,15 @@ func (d *DataDispatcher) worker(id int) {
 }
 
 func (d *DataDispatcher) process(data interface{}) {
-	if err := d.producer.Produce(d.ctx, "vehicle_data", "", data); err != nil {
+	topic, key := "vehicle_data", ""
+	if p, ok := data.(MQPayload); ok {
+		// 消息可指定目标 Topic / 路由键 (如无效 VIN 隔离)
+		if p.Topic != "" {
+			topic = p.Topic
+		}
+		key = p.Key
+	}
+	if err := d.producer.Produce(d.ctx, topic, key, data); err != nil {
 		d.logger.Error("DataDispatcher failed to send data", zap.Error(err))
 	}
 
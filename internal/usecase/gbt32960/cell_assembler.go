@@ -0,0 +1,251 @@
+package gbt32960
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/protocol/gbt32960"
+	"vehicle-gateway/internal/usecase"
+)
+
+// DefaultCellAssemblyTimeout 未配置时等待剩余分帧的时长
+const DefaultCellAssemblyTimeout = 30 * time.Second
+
+// MsgStorageVoltagePack 拼接完成的整包单体电压消息类型
+const MsgStorageVoltagePack = "STORAGE_VOLTAGE_PACK"
+
+// CellVoltagePack 一个可充电储能子系统在同一采集时间的完整单体电压
+type CellVoltagePack struct {
+	SystemNo        byte      // 子系统号
+	Voltage         float32   // 子系统电压 (V)
+	Current         float32   // 子系统电流 (A)
+	SingleCellCount uint16    // 单体电池总数
+	CellVoltages    []float32 // 单体电压 (V), 按电池序号排列, 超时缺失的单体标记为无效 (发布为 null)
+	Frames          int       // 收到的分帧数
+	Complete        bool      // 是否收齐全部单体
+	MissingCells    []uint16  // 超时未收到的单体序号 (从 1 开始)
+
+	Validity gbt32960.FieldStatus `json:",omitempty"` // 异常/无效的单体电压 (CellVoltages[下标])
+}
+
+// cellVoltagePackUnits 整包单体电压的物理量单位
+var cellVoltagePackUnits = map[string]string{
+	"Voltage":      gbt32960.UnitVolt,
+	"Current":      gbt32960.UnitAmpere,
+	"CellVoltages": gbt32960.UnitVolt,
+}
+
+// Units 物理量单位
+func (p *CellVoltagePack) Units() map[string]string { return cellVoltagePackUnits }
+
+// assemblyKey 拼接关联键: 同一车辆、同一子系统、同一采集时间的分帧属于同一组
+type assemblyKey struct {
+	vin         string
+	systemNo    byte
+	collectTime int64
+}
+
+type cellAssembly struct {
+	pack      *CellVoltagePack
+	received  []bool
+	remaining int
+	base      usecase.MQPayload // 首个分帧的消息模板 (采集时间、VIN 标记等)
+	firstSeen time.Time
+}
+
+// CellAssembler 拼接 2016 版分帧上报的单体电压 (0x08)
+// 收齐后立即发布，超时未收齐的按不完整数据发布
+type CellAssembler struct {
+	mu         sync.Mutex
+	pending    map[assemblyKey]*cellAssembly
+	enabled    bool
+	timeout    time.Duration
+	dispatcher *usecase.DataDispatcher
+	logger     *zap.Logger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewCellAssembler 创建单体电压拼接器
+func NewCellAssembler(cfg config.CellAssemblyConfig, dispatcher *usecase.DataDispatcher, logger *zap.Logger) *CellAssembler {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCellAssemblyTimeout
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &CellAssembler{
+		pending:    make(map[assemblyKey]*cellAssembly),
+		enabled:    cfg.Enabled,
+		timeout:    timeout,
+		dispatcher: dispatcher,
+		logger:     logger,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Start 启动超时清理协程
+func (a *CellAssembler) Start() {
+	if !a.enabled {
+		return
+	}
+	interval := a.timeout / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case now := <-ticker.C:
+				a.sweep(now)
+			}
+		}
+	}()
+	a.logger.Info("CellAssembler started", zap.Duration("timeout", a.timeout))
+}
+
+// Stop 停止清理协程，并发布所有未收齐的数据
+func (a *CellAssembler) Stop() {
+	a.cancel()
+	a.wg.Wait()
+	a.sweep(time.Time{})
+}
+
+// Add 加入一帧单体电压数据，base 为发布整包消息时沿用的消息模板
+func (a *CellAssembler) Add(base usecase.MQPayload, sub gbt32960.StorageSubsystemInfo) {
+	if a == nil || !a.enabled || sub.SingleCellCount == 0 {
+		return
+	}
+
+	start := int(sub.StartFrameSeq)
+	end := start + len(sub.CellVoltages) - 1
+	if start < 1 || end > int(sub.SingleCellCount) {
+		a.logger.Warn("Cell voltage frame out of range",
+			zap.String("vin", base.VIN),
+			zap.Uint8("system_no", sub.SystemNo),
+			zap.Uint16("start", sub.StartFrameSeq),
+			zap.Int("count", len(sub.CellVoltages)),
+			zap.Uint16("total", sub.SingleCellCount))
+		return
+	}
+
+	key := assemblyKey{vin: base.VIN, systemNo: sub.SystemNo, collectTime: base.CollectTime.Unix()}
+
+	a.mu.Lock()
+	asm, ok := a.pending[key]
+	if ok && asm.pack.SingleCellCount != sub.SingleCellCount {
+		a.mu.Unlock()
+		a.logger.Warn("Cell voltage frame total mismatch",
+			zap.String("vin", base.VIN),
+			zap.Uint8("system_no", sub.SystemNo),
+			zap.Uint16("expected", asm.pack.SingleCellCount),
+			zap.Uint16("actual", sub.SingleCellCount))
+		return
+	}
+	if !ok {
+		asm = &cellAssembly{
+			pack: &CellVoltagePack{
+				SystemNo:        sub.SystemNo,
+				Voltage:         sub.Voltage,
+				Current:         sub.Current,
+				SingleCellCount: sub.SingleCellCount,
+				CellVoltages:    make([]float32, sub.SingleCellCount),
+			},
+			received:  make([]bool, sub.SingleCellCount),
+			remaining: int(sub.SingleCellCount),
+			base:      base,
+			firstSeen: time.Now(),
+		}
+		a.pending[key] = asm
+	}
+
+	asm.pack.Frames++
+	for i, v := range sub.CellVoltages {
+		idx := start - 1 + i
+		if !asm.received[idx] {
+			asm.received[idx] = true
+			asm.remaining--
+		}
+		asm.pack.CellVoltages[idx] = v
+		cell := cellKey(idx)
+		if state, ok := sub.Validity[cellKey(i)]; ok {
+			asm.pack.mark(cell, state)
+		} else {
+			delete(asm.pack.Validity, cell)
+		}
+	}
+
+	if asm.remaining > 0 {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.pending, key)
+	a.mu.Unlock()
+
+	asm.pack.Complete = true
+	a.publish(asm)
+}
+
+// sweep 发布超时未收齐的数据，now 为零值时发布全部
+func (a *CellAssembler) sweep(now time.Time) {
+	var expired []*cellAssembly
+
+	a.mu.Lock()
+	for key, asm := range a.pending {
+		if now.IsZero() || now.Sub(asm.firstSeen) >= a.timeout {
+			delete(a.pending, key)
+			expired = append(expired, asm)
+		}
+	}
+	a.mu.Unlock()
+
+	for _, asm := range expired {
+		for i, ok := range asm.received {
+			if !ok {
+				asm.pack.MissingCells = append(asm.pack.MissingCells, uint16(i+1))
+				asm.pack.mark(cellKey(i), gbt32960.FieldInvalid)
+			}
+		}
+		a.logger.Warn("Cell voltage assembly incomplete",
+			zap.String("vin", asm.base.VIN),
+			zap.Uint8("system_no", asm.pack.SystemNo),
+			zap.Int("frames", asm.pack.Frames),
+			zap.Int("missing", len(asm.pack.MissingCells)))
+		a.publish(asm)
+	}
+}
+
+// cellKey 单体电压在 Validity 中的键
+func cellKey(i int) string {
+	return fmt.Sprintf("CellVoltages[%d]", i)
+}
+
+// mark 记录单体电压的异常/无效状态
+func (p *CellVoltagePack) mark(cell string, state gbt32960.FieldState) {
+	if p.Validity == nil {
+		p.Validity = make(gbt32960.FieldStatus)
+	}
+	p.Validity[cell] = state
+}
+
+func (a *CellAssembler) publish(asm *cellAssembly) {
+	if a.dispatcher == nil {
+		return
+	}
+	p := asm.base
+	p.Type = MsgStorageVoltagePack
+	p.Data = asm.pack
+	a.dispatcher.Dispatch(p)
+}
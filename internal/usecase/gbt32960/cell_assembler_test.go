@@ -0,0 +1,83 @@
+package gbt32960
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/protocol/gbt32960"
+	"vehicle-gateway/internal/usecase"
+)
+
+// captureProducer 记录分发器投递的消息
+type captureProducer struct {
+	out chan interface{}
+}
+
+func (c *captureProducer) Produce(_ context.Context, _, _ string, data interface{}) error {
+	c.out <- data
+	return nil
+}
+
+func (c *captureProducer) Close() {}
+
+func TestCellAssemblerMarksMissingCellsInvalid(t *testing.T) {
+	producer := &captureProducer{out: make(chan interface{}, 1)}
+	dispatcher := usecase.NewDataDispatcher(producer, 1, zap.NewNop())
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	cells := NewCellAssembler(config.CellAssemblyConfig{Enabled: true}, dispatcher, zap.NewNop())
+	base := usecase.MQPayload{VIN: testVIN, CollectTime: time.Date(2026, 10, 16, 8, 30, 0, 0, time.Local)}
+	cells.Add(base, gbt32960.StorageSubsystemInfo{
+		SystemNo:        1,
+		SingleCellCount: 4,
+		StartFrameSeq:   2,
+		FrameCellCount:  2,
+		CellVoltages:    []float32{3.3, 0},
+		Validity:        gbt32960.FieldStatus{"CellVoltages[1]": gbt32960.FieldAbnormal},
+	})
+	cells.Stop()
+
+	var p usecase.MQPayload
+	select {
+	case data := <-producer.out:
+		p = data.(usecase.MQPayload)
+	case <-time.After(time.Second):
+		t.Fatal("超时未发布不完整的整包")
+	}
+
+	pack := p.Data.(*CellVoltagePack)
+	if pack.Complete || !reflect.DeepEqual(pack.MissingCells, []uint16{1, 4}) {
+		t.Errorf("Complete = %v, MissingCells = %v", pack.Complete, pack.MissingCells)
+	}
+	wantValidity := gbt32960.FieldStatus{
+		"CellVoltages[0]": gbt32960.FieldInvalid,
+		"CellVoltages[2]": gbt32960.FieldAbnormal,
+		"CellVoltages[3]": gbt32960.FieldInvalid,
+	}
+	if !reflect.DeepEqual(pack.Validity, wantValidity) {
+		t.Errorf("Validity = %v, 期望 %v", pack.Validity, wantValidity)
+	}
+
+	raw, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Data struct{ CellVoltages []*float32 } `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range out.Data.CellVoltages {
+		if (v == nil) != (i != 1) {
+			t.Errorf("CellVoltages[%d] = %v, 仅下标 1 应有值", i, v)
+		}
+	}
+}
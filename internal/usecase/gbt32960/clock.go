@@ -0,0 +1,134 @@
+package gbt32960
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
+	"vehicle-gateway/internal/usecase"
+)
+
+// 采集时间异常标记
+const (
+	TimeFlagInvalid = "INVALID_DATE" // 年月日时分秒字段越界 (如月份 13)
+	TimeFlagFuture  = "FUTURE"       // 采集时间超前接收时间
+	TimeFlagStale   = "STALE"        // 实时数据采集时间过于滞后
+)
+
+// collectTiming 单个报文的采集时间、接收时间与时钟偏差
+type collectTiming struct {
+	collect time.Time
+	receive time.Time
+	drift   time.Duration // receive - collect, 为负表示终端时钟超前
+	flags   []string
+}
+
+// payload 构建携带时间信息的消息
+func (t collectTiming) payload(msgType, vin string, data interface{}, reissue bool) usecase.MQPayload {
+	return usecase.MQPayload{
+		Type:        msgType,
+		VIN:         vin,
+		Data:        data,
+		Reissue:     reissue,
+		CollectTime: t.collect,
+		ReceiveTime: t.receive,
+		ClockDrift:  t.drift,
+		TimeFlags:   t.flags,
+	}
+}
+
+// DriftStats 车辆时钟偏差统计 (接收时间 - 采集时间, 补发数据不参与统计)
+type DriftStats struct {
+	Samples   uint64        // 统计的报文数
+	Flagged   uint64        // 采集时间异常的报文数
+	Last      time.Duration // 最近一次偏差
+	Min       time.Duration
+	Max       time.Duration
+	Mean      time.Duration
+	UpdatedAt time.Time
+}
+
+type driftTracker struct {
+	mu    sync.Mutex
+	stats DriftStats
+	total time.Duration
+}
+
+// checkCollectTime 校验采集时间并计算时钟偏差，实时数据计入车辆偏差统计
+func (h *Handler) checkCollectTime(vin string, raw []byte, collectTime time.Time, reissue bool) collectTiming {
+	t := collectTiming{collect: collectTime, receive: time.Now()}
+	t.drift = t.receive.Sub(collectTime)
+
+	ct := h.cfg.CollectTime
+	if err := gbt32960.CheckCollectTime(raw); err != nil {
+		t.flags = append(t.flags, TimeFlagInvalid)
+	}
+	if ct.MaxFuture > 0 && -t.drift > ct.MaxFuture {
+		t.flags = append(t.flags, TimeFlagFuture)
+	}
+	if !reissue && ct.MaxPast > 0 && t.drift > ct.MaxPast {
+		t.flags = append(t.flags, TimeFlagStale)
+	}
+	if len(t.flags) > 0 {
+		h.logger.Warn("Collect time out of range",
+			zap.String("vin", vin),
+			zap.Time("collect_time", collectTime),
+			zap.Duration("drift", t.drift),
+			zap.Strings("flags", t.flags))
+	}
+
+	if !reissue {
+		h.recordDrift(vin, t)
+	}
+	return t
+}
+
+func (h *Handler) recordDrift(vin string, t collectTiming) {
+	v, _ := h.drifts.LoadOrStore(vin, &driftTracker{})
+	d := v.(*driftTracker)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	s := &d.stats
+	if len(t.flags) > 0 {
+		s.Flagged++
+	}
+	if s.Samples == 0 || t.drift < s.Min {
+		s.Min = t.drift
+	}
+	if s.Samples == 0 || t.drift > s.Max {
+		s.Max = t.drift
+	}
+	s.Samples++
+	d.total += t.drift
+	s.Mean = d.total / time.Duration(s.Samples)
+	s.Last = t.drift
+	s.UpdatedAt = t.receive
+}
+
+// ClockDrift 返回车辆的时钟偏差统计
+func (h *Handler) ClockDrift(vin string) (DriftStats, bool) {
+	v, ok := h.drifts.Load(vin)
+	if !ok {
+		return DriftStats{}, false
+	}
+	d := v.(*driftTracker)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats, true
+}
+
+// ClockDrifts 返回所有车辆的时钟偏差统计
+func (h *Handler) ClockDrifts() map[string]DriftStats {
+	out := make(map[string]DriftStats)
+	h.drifts.Range(func(key, value interface{}) bool {
+		d := value.(*driftTracker)
+		d.mu.Lock()
+		out[key.(string)] = d.stats
+		d.mu.Unlock()
+		return true
+	})
+	return out
+}
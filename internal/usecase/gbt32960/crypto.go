@@ -0,0 +1,83 @@
+package gbt32960
+
+import (
+	"fmt"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+// decrypt 将加密的数据单元就地解密为明文
+func (h *Handler) decrypt(conn Conn, packet *gbt32960.Packet) error {
+	cipher, err := h.cipher(packet.Encryption, conn, packet.VIN)
+	if err != nil {
+		return err
+	}
+	plain, err := cipher.Decrypt(packet.DataUnit)
+	if err != nil {
+		return err
+	}
+	packet.DataUnit = plain
+	return nil
+}
+
+// send 编码并发送报文，仅可在连接所在的事件循环中调用
+func (h *Handler) send(conn Conn, pkt *gbt32960.Packet) error {
+	frame, err := h.encode(conn, pkt)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Write(frame)
+	return err
+}
+
+// sendAsync 编码并异步发送报文，用于事件循环之外发起的下行报文
+func (h *Handler) sendAsync(conn Conn, pkt *gbt32960.Packet) error {
+	frame, err := h.encode(conn, pkt)
+	if err != nil {
+		return err
+	}
+	return conn.AsyncWrite(frame)
+}
+
+// encode 编码报文，对端协商了数据单元加密时按相同方式加密
+func (h *Handler) encode(conn Conn, pkt *gbt32960.Packet) ([]byte, error) {
+	if enc := h.negotiatedEncryption(conn, pkt.VIN); gbt32960.IsEncrypted(enc) && len(pkt.DataUnit) > 0 {
+		cipher, err := h.cipher(enc, conn, pkt.VIN)
+		if err != nil {
+			return nil, fmt.Errorf("数据单元加密失败: %w", err)
+		}
+		data, err := cipher.Encrypt(pkt.DataUnit)
+		if err != nil {
+			return nil, fmt.Errorf("数据单元加密失败: %w", err)
+		}
+		encrypted := *pkt
+		encrypted.Encryption = enc
+		encrypted.DataUnit = data
+		pkt = &encrypted
+	}
+
+	return gbt32960.EncodePacket(pkt), nil
+}
+
+// negotiatedEncryption 返回与对端协商的加密方式: 车辆会话优先，其次为平台链路
+func (h *Handler) negotiatedEncryption(conn Conn, vin string) byte {
+	if sess, ok := h.SessionMgr.Get(vin); ok && sess.Conn.RemoteAddr() == conn.RemoteAddr() && sess.Encryption != 0 {
+		return sess.Encryption
+	}
+	if link, ok := h.SessionMgr.GetLink(conn.RemoteAddr()); ok && link.Encryption != 0 {
+		return link.Encryption
+	}
+	return gbt32960.EncryptionNone
+}
+
+// cipher 按 VIN 及所在平台账号查找密钥
+func (h *Handler) cipher(method byte, conn Conn, vin string) (gbt32960.Cipher, error) {
+	if h.Keys == nil {
+		return nil, gbt32960.ErrNoKey
+	}
+	var platform string
+	if link, ok := h.SessionMgr.GetLink(conn.RemoteAddr()); ok {
+		platform = link.Username
+	}
+	return h.Keys.Cipher(method, vin, platform)
+}
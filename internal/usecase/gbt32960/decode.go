@@ -0,0 +1,38 @@
+package gbt32960
+
+import (
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+// 实时数据解码模式, 车辆登入数据存在多余字节时同样适用
+const (
+	DecodeStrict  = "strict"  // 任一信息单元异常即拒绝整包, 不发布任何数据
+	DecodeLenient = "lenient" // 发布已解析的 (部分) 数据, 并发布诊断信息
+)
+
+// MsgDecodeDiagnostics 宽松模式下的解析诊断消息类型
+const MsgDecodeDiagnostics = "DECODE_DIAGNOSTICS"
+
+// DecodeDiagnostics 单个报文的解析诊断
+type DecodeDiagnostics struct {
+	Mode        string                 // 解码模式
+	Command     byte                   // 命令标识 (0x02 实时 / 0x04 补发)
+	Diagnostics []*gbt32960.ParseError // 解析错误列表
+}
+
+// decodeMode 返回连接所属平台账号的解码模式，未单独配置时使用全局模式
+func (h *Handler) decodeMode(conn Conn) string {
+	mode := h.cfg.Decode.Mode
+	if link, ok := h.SessionMgr.GetLink(conn.RemoteAddr()); ok && link.Username != "" {
+		for _, p := range h.cfg.Decode.Platforms {
+			if p.Username == link.Username {
+				mode = p.Mode
+				break
+			}
+		}
+	}
+	if mode == DecodeLenient {
+		return DecodeLenient
+	}
+	return DecodeStrict
+}
@@ -0,0 +1,244 @@
+package gbt32960
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+var (
+	// ErrVehicleOffline 目标车辆没有在线会话
+	ErrVehicleOffline = errors.New("车辆不在线")
+	// ErrRequestInFlight 同一车辆的同一下行命令尚未收到应答
+	ErrRequestInFlight = errors.New("存在未完成的同类下行请求")
+	// ErrRequestTimeout 等待车辆应答超时
+	ErrRequestTimeout = errors.New("等待车辆应答超时")
+)
+
+// DefaultRequestTimeout 调用方未设置截止时间时的默认应答超时
+const DefaultRequestTimeout = 10 * time.Second
+
+// pendingKey 下行请求的关联键
+// 下行命令与应答没有流水号可供匹配，同一车辆同一命令同时只允许一个请求在途
+type pendingKey struct {
+	vin string
+	cmd byte
+}
+
+// request 向在线车辆发送下行命令并等待同命令字的应答
+func (h *Handler) request(ctx context.Context, vin string, cmd byte, dataUnit []byte) (*gbt32960.Packet, error) {
+	sess, ok := h.SessionMgr.Get(vin)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrVehicleOffline, vin)
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultRequestTimeout)
+		defer cancel()
+	}
+
+	key := pendingKey{vin: vin, cmd: cmd}
+	replyC := make(chan *gbt32960.Packet, 1)
+	if _, loaded := h.pending.LoadOrStore(key, replyC); loaded {
+		return nil, ErrRequestInFlight
+	}
+	defer h.pending.CompareAndDelete(key, replyC)
+
+	pkt := &gbt32960.Packet{
+		Version:    sess.Version,
+		Command:    cmd,
+		Response:   0xFE, // Command
+		VIN:        vin,
+		Encryption: 0x01,
+		DataUnit:   dataUnit,
+	}
+	if err := h.sendAsync(sess.Conn, pkt); err != nil {
+		return nil, fmt.Errorf("下发命令 0x%02X 失败: %v", cmd, err)
+	}
+	h.logger.Info("Downlink Request Sent", zap.String("vin", vin), zap.Uint8("command", cmd))
+
+	select {
+	case reply := <-replyC:
+		return reply, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ErrRequestTimeout
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// resolve 将车辆应答交给等待中的下行请求，没有对应请求时返回 false
+func (h *Handler) resolve(packet *gbt32960.Packet) bool {
+	val, ok := h.pending.LoadAndDelete(pendingKey{vin: packet.VIN, cmd: packet.Command})
+	if !ok {
+		return false
+	}
+	val.(chan *gbt32960.Packet) <- packet
+	return true
+}
+
+// handleDownlinkReply 处理车辆对下行命令的应答
+func (h *Handler) handleDownlinkReply(conn Conn, packet *gbt32960.Packet) error {
+	h.SessionMgr.UpdateLastActive(packet.VIN)
+	if packet.Response == 0xFE {
+		return fmt.Errorf("不支持终端发起的命令 0x%02X", packet.Command)
+	}
+	if !h.resolve(packet) {
+		h.logger.Warn("Unsolicited downlink reply",
+			zap.String("vin", packet.VIN),
+			zap.Uint8("command", packet.Command),
+			zap.String("remote_addr", conn.RemoteAddr()))
+	}
+	return nil
+}
+
+// replyError 将应答标志转换为错误
+func replyError(reply *gbt32960.Packet) error {
+	if reply.Response == 0x01 {
+		return nil
+	}
+	return fmt.Errorf("车辆应答失败 (命令 0x%02X, 应答标志 0x%02X)", reply.Command, reply.Response)
+}
+
+// QueryParams 查询车载终端参数 (0x80)
+// 超时由 ctx 控制，未设置截止时间时使用 DefaultRequestTimeout
+func (h *Handler) QueryParams(ctx context.Context, vin string, ids []gbt32960.ParamID) ([]gbt32960.TerminalParam, error) {
+	if len(ids) == 0 || len(ids) > 0xFF {
+		return nil, fmt.Errorf("参数个数无效: %d", len(ids))
+	}
+
+	reply, err := h.request(ctx, vin, gbt32960.CmdQueryParams, gbt32960.BuildParamQuery(time.Now(), ids))
+	if err != nil {
+		return nil, err
+	}
+	if err := replyError(reply); err != nil {
+		return nil, err
+	}
+
+	data, err := gbt32960.ParseParamQueryResponse(reply.DataUnit)
+	if err != nil {
+		return nil, fmt.Errorf("参数查询应答解析失败: %v", err)
+	}
+	return data.Params, nil
+}
+
+// SetParams 设置车载终端参数 (0x81)，返回每个参数的设置结果
+// params 的值类型需与参数定义一致: WORD -> uint16, BYTE -> byte, STRING -> string
+func (h *Handler) SetParams(ctx context.Context, vin string, params map[gbt32960.ParamID]interface{}) ([]gbt32960.ParamSetResult, error) {
+	dataUnit, err := gbt32960.BuildParamSet(time.Now(), params)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]gbt32960.ParamID, 0, len(params))
+	for id := range params {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	reply, err := h.request(ctx, vin, gbt32960.CmdSetParams, dataUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := gbt32960.ParseParamSetResponse(reply.Response, reply.DataUnit, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range results {
+		if !r.Success {
+			h.logger.Warn("Parameter set rejected",
+				zap.String("vin", vin),
+				zap.Uint8("param_id", uint8(r.ID)),
+				zap.Uint8("response", reply.Response))
+		}
+	}
+	return results, nil
+}
+
+// RemoteUpgrade 下发远程升级命令
+func (h *Handler) RemoteUpgrade(ctx context.Context, vin string, p gbt32960.RemoteUpgradeParams) (*ControlOutcome, error) {
+	dataUnit, err := gbt32960.BuildRemoteUpgrade(time.Now(), p)
+	if err != nil {
+		return nil, err
+	}
+	return h.control(ctx, vin, gbt32960.ControlRemoteUpgrade, dataUnit)
+}
+
+// ShutdownTerminal 下发车载终端关机命令
+func (h *Handler) ShutdownTerminal(ctx context.Context, vin string) (*ControlOutcome, error) {
+	return h.control(ctx, vin, gbt32960.ControlShutdown, gbt32960.BuildTerminalShutdown(time.Now()))
+}
+
+// ResetTerminal 下发车载终端复位命令
+func (h *Handler) ResetTerminal(ctx context.Context, vin string) (*ControlOutcome, error) {
+	return h.control(ctx, vin, gbt32960.ControlReset, gbt32960.BuildTerminalReset(time.Now()))
+}
+
+// FactoryResetTerminal 下发车载终端恢复出厂设置命令
+func (h *Handler) FactoryResetTerminal(ctx context.Context, vin string) (*ControlOutcome, error) {
+	return h.control(ctx, vin, gbt32960.ControlFactoryReset, gbt32960.BuildFactoryReset(time.Now()))
+}
+
+// DisconnectTerminal 下发断开数据通信链路命令
+func (h *Handler) DisconnectTerminal(ctx context.Context, vin string) (*ControlOutcome, error) {
+	return h.control(ctx, vin, gbt32960.ControlDisconnect, gbt32960.BuildDisconnectLink(time.Now()))
+}
+
+// AlarmTerminal 下发车载终端报警/预警命令
+func (h *Handler) AlarmTerminal(ctx context.Context, vin string, p gbt32960.TerminalAlarmParams) (*ControlOutcome, error) {
+	return h.control(ctx, vin, gbt32960.ControlAlarm, gbt32960.BuildTerminalAlarm(time.Now(), p))
+}
+
+// LastControlOutcome 获取车辆最近一次指定终端控制命令的结果
+func (h *Handler) LastControlOutcome(vin string, cmd gbt32960.ControlCommand) (*ControlOutcome, bool) {
+	val, ok := h.controls.Load(pendingKey{vin: vin, cmd: byte(cmd)})
+	if !ok {
+		return nil, false
+	}
+	return val.(*ControlOutcome), true
+}
+
+// control 下发终端控制命令并记录、发布执行结果
+// 车辆离线等未能下发的情况直接返回错误；已下发但超时或被拒绝的情况同样记录为结果
+func (h *Handler) control(ctx context.Context, vin string, cmd gbt32960.ControlCommand, dataUnit []byte) (*ControlOutcome, error) {
+	outcome := &ControlOutcome{Command: cmd, SentTime: time.Now()}
+
+	reply, err := h.request(ctx, vin, gbt32960.CmdTerminalControl, dataUnit)
+	switch {
+	case errors.Is(err, ErrVehicleOffline), errors.Is(err, ErrRequestInFlight):
+		return nil, err
+	case err != nil:
+		outcome.Error = err.Error()
+	default:
+		outcome.ReplyTime = time.Now()
+		outcome.Response = reply.Response
+		result, perr := gbt32960.ParseTerminalControlResponse(reply.Response, reply.DataUnit)
+		if perr != nil {
+			outcome.Error = fmt.Sprintf("终端控制应答解析失败: %v", perr)
+		} else {
+			outcome.Success = result.Success
+			if result.Command != 0 && result.Command != cmd {
+				outcome.Success = false
+				outcome.Error = fmt.Sprintf("应答命令 ID 不匹配: 0x%02X", byte(result.Command))
+			}
+		}
+	}
+
+	h.controls.Store(pendingKey{vin: vin, cmd: byte(cmd)}, outcome)
+	h.publish(EventTerminalControl, vin, outcome)
+	h.logger.Info("Terminal Control",
+		zap.String("vin", vin),
+		zap.Uint8("control", uint8(cmd)),
+		zap.Bool("success", outcome.Success),
+		zap.String("error", outcome.Error))
+	return outcome, nil
+}
@@ -0,0 +1,45 @@
+package gbt32960
+
+import (
+	"time"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+// MQ 事件消息类型
+const (
+	EventPlatformLogin      = "PLATFORM_LOGIN"
+	EventPlatformLogout     = "PLATFORM_LOGOUT"
+	EventPlatformDisconnect = "PLATFORM_DISCONNECT"
+	EventTerminalControl    = "TERMINAL_CONTROL"
+	EventSessionConflict    = "SESSION_CONFLICT"
+)
+
+// PlatformLinkEvent 平台链路生命周期事件 (登入/登出/断开)
+type PlatformLinkEvent struct {
+	Username   string    `json:"username"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Success    bool      `json:"success"`
+	LoginTime  time.Time `json:"loginTime"`
+	EventTime  time.Time `json:"eventTime"`
+	Vehicles   []string  `json:"vehicles,omitempty"` // 随链路一并下线的车辆
+}
+
+// ControlOutcome 车载终端控制 (0x82) 的执行结果
+type ControlOutcome struct {
+	Command   gbt32960.ControlCommand `json:"command"`
+	Success   bool                    `json:"success"`
+	Response  byte                    `json:"response"` // 应答标志 (未收到应答时为 0)
+	Error     string                  `json:"error,omitempty"`
+	SentTime  time.Time               `json:"sentTime"`
+	ReplyTime time.Time               `json:"replyTime"`
+}
+
+// SessionConflictEvent 同一 VIN 从不同连接重复登入 (克隆终端或 VIN 配置错误)
+type SessionConflictEvent struct {
+	Policy        string    `json:"policy"`
+	OldRemoteAddr string    `json:"oldRemoteAddr"`
+	NewRemoteAddr string    `json:"newRemoteAddr"`
+	OldLoginTime  time.Time `json:"oldLoginTime"`
+	EventTime     time.Time `json:"eventTime"`
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
+	"vehicle-gateway/internal/config"
 	"vehicle-gateway/internal/protocol/gbt32960"
 	"vehicle-gateway/internal/usecase"
 
 	"runtime/debug"
+	"sync"
 
 	"go.uber.org/zap"
 )
@@ -15,19 +18,52 @@ import (
 // Protocol Commands will be used from package gbt32960 directly
 
 type Handler struct {
-	SessionMgr *SessionManager
-	Dispatcher *usecase.DataDispatcher
-	Auth       AuthService
-	logger     *zap.Logger
+	SessionMgr  *SessionManager
+	Dispatcher  *usecase.DataDispatcher
+	Auth        AuthService
+	Keys        *KeyStore
+	Decoders    *gbt32960.Registry      // 实时数据信息类型解码器, 可注册自定义解码器
+	Custom      *gbt32960.CustomDecoder // 车企自定义信息类型 (YAML 布局描述)
+	Cells       *CellAssembler          // 分帧单体电压拼接
+	cfg         config.GBT32960Config
+	mqType      string // 生效的消息队列类型 (rabbitmq / kafka), MQ 关闭时为空
+	logger      *zap.Logger
+	pending     sync.Map // map[pendingKey]chan *gbt32960.Packet 等待应答的下行请求
+	controls    sync.Map // map[pendingKey]*ControlOutcome 最近一次终端控制结果
+	drifts      sync.Map // map[string]*driftTracker 车辆时钟偏差统计
+	invalidVINs sync.Map // map[string]*uint64 各来源 IP 的无效 VIN 报文计数 (原子操作)
 }
 
-func NewHandler(sm *SessionManager, dispatcher *usecase.DataDispatcher, auth AuthService, logger *zap.Logger) *Handler {
+func NewHandler(sm *SessionManager, dispatcher *usecase.DataDispatcher, auth AuthService, keys *KeyStore, custom *gbt32960.CustomDecoder, cells *CellAssembler, cfg config.GBT32960Config, mq config.MessageQueueConfig, logger *zap.Logger) (*Handler, error) {
+	switch cfg.Session.DuplicatePolicy {
+	case "", "reject", "kick", "allow":
+	default:
+		return nil, fmt.Errorf("session: 无效的 duplicate_policy: %s (可选 reject / kick / allow)", cfg.Session.DuplicatePolicy)
+	}
+	mqType := ""
+	if mq.Enabled {
+		mqType = mq.Type
+	}
+	if err := validateVINConfig(cfg.VINValidation, mqType); err != nil {
+		return nil, err
+	}
+	for _, cs := range cfg.Location.CoordinateSystems {
+		if cs != gbt32960.CoordGCJ02 && cs != gbt32960.CoordBD09 {
+			logger.Warn("Unknown coordinate system ignored", zap.String("coordinate_system", cs))
+		}
+	}
 	return &Handler{
 		SessionMgr: sm,
 		Dispatcher: dispatcher,
 		Auth:       auth,
+		Keys:       keys,
+		Decoders:   gbt32960.NewStandardRegistry(),
+		Custom:     custom,
+		Cells:      cells,
+		cfg:        cfg,
+		mqType:     mqType,
 		logger:     logger,
-	}
+	}, nil
 }
 
 // HandleMessage 处理单个解析后的报文
@@ -43,15 +79,38 @@ func (h *Handler) HandleMessage(conn Conn, packet *gbt32960.Packet) (err error)
 		}
 	}()
 
+	if gbt32960.IsEncrypted(packet.Encryption) {
+		if err := h.decrypt(conn, packet); err != nil {
+			return fmt.Errorf("数据单元解密失败 (加密方式 0x%02X): %w", packet.Encryption, err)
+		}
+	}
+
+	switch packet.Command {
+	case gbt32960.CmdVehicleLogin, gbt32960.CmdRealTime, gbt32960.CmdReissue:
+		if err := h.checkVIN(conn, packet); err != nil {
+			return err
+		}
+	}
+
 	switch packet.Command {
 	case gbt32960.CmdPlatformLogin:
 		return h.handlePlatformLogin(conn, packet)
 	case gbt32960.CmdVehicleLogin:
 		return h.handleVehicleLogin(conn, packet)
 	case gbt32960.CmdRealTime:
-		return h.handleRealTime(conn, packet)
+		return h.handleRealTime(conn, packet, false)
+	case gbt32960.CmdReissue:
+		return h.handleRealTime(conn, packet, true)
 	case gbt32960.CmdLogout:
 		return h.handleLogout(conn, packet)
+	case gbt32960.CmdPlatformLogout:
+		return h.handlePlatformLogout(conn, packet)
+	case gbt32960.CmdHeartbeat:
+		return h.handleHeartbeat(conn, packet)
+	case gbt32960.CmdTimeCalibration:
+		return h.handleTimeCalibration(conn, packet)
+	case gbt32960.CmdQueryParams, gbt32960.CmdSetParams, gbt32960.CmdTerminalControl:
+		return h.handleDownlinkReply(conn, packet)
 	default:
 		// 其他命令更新活跃时间
 		h.SessionMgr.UpdateLastActive(packet.VIN)
@@ -98,28 +157,114 @@ func (h *Handler) handlePlatformLogin(conn Conn, packet *gbt32960.Packet) error
 
 	respData := gbt32960.BuildGeneralResponse(reqTime)
 	respPkt := &gbt32960.Packet{
+		Version:    packet.Version,
 		Command:    gbt32960.CmdPlatformLogin,
 		Response:   respFlag, // Set Header Response Flag
 		VIN:        packet.VIN,
 		Encryption: 0x01,
 		DataUnit:   respData,
 	}
-	respBytes := gbt32960.EncodePacket(respPkt)
 
-	if _, err := conn.Write(respBytes); err != nil {
+	event := PlatformLinkEvent{
+		Username:   loginData.Username,
+		RemoteAddr: conn.RemoteAddr(),
+		Success:    success,
+		EventTime:  time.Now(),
+	}
+
+	if success {
+		// Mark session as platform authenticated
+		// 先登记链路及加密规则，使登入应答按协商的方式加密
+		conn.SetPlatformAuthenticated(true)
+		link := h.SessionMgr.PlatformLogin(conn.RemoteAddr(), loginData.Username)
+		link.Encryption = loginData.EncryptRule
+		event.LoginTime = link.LoginTime
+	}
+
+	if err := h.send(conn, respPkt); err != nil {
 		h.logger.Error("Failed to send platform login response", zap.Error(err))
 	}
 
+	h.publish(EventPlatformLogin, packet.VIN, event)
 	if !success {
 		return errors.New("平台鉴权失败，拒绝连接")
 	}
 
-	// Mark session as platform authenticated
-	conn.SetPlatformAuthenticated(true)
+	return nil
+}
+
+func (h *Handler) handlePlatformLogout(conn Conn, packet *gbt32960.Packet) error {
+	logoutData, err := gbt32960.ParsePlatformLogout(packet.DataUnit)
+	if err != nil {
+		return fmt.Errorf("平台登出解析失败: %v", err)
+	}
+
+	// Response: [Time 6] (General Response)
+	respPkt := &gbt32960.Packet{
+		Version:    packet.Version,
+		Command:    gbt32960.CmdPlatformLogout,
+		Response:   0x01, // Success
+		VIN:        packet.VIN,
+		Encryption: 0x01,
+		DataUnit:   gbt32960.BuildGeneralResponse(packet.DataUnit[:6]),
+	}
+	if err := h.send(conn, respPkt); err != nil {
+		h.logger.Error("Failed to send platform logout response", zap.Error(err))
+	}
+
+	conn.SetPlatformAuthenticated(false)
+	link, vins := h.SessionMgr.RemoveLink(conn.RemoteAddr())
+
+	event := PlatformLinkEvent{
+		RemoteAddr: conn.RemoteAddr(),
+		Success:    true,
+		EventTime:  time.Now(),
+		Vehicles:   vins,
+	}
+	if link != nil {
+		event.Username = link.Username
+		event.LoginTime = link.LoginTime
+	}
+
+	h.logger.Info("Platform Logout Request",
+		zap.String("username", event.Username),
+		zap.Uint16("seq", logoutData.LogoutSeq),
+		zap.Strings("vins", vins))
+	h.publish(EventPlatformLogout, packet.VIN, event)
 
 	return nil
 }
 
+// HandleDisconnect 处理连接断开: 清理平台链路及其上的车辆会话
+func (h *Handler) HandleDisconnect(remoteAddr string) {
+	link, vins := h.SessionMgr.RemoveLink(remoteAddr)
+	if link == nil || link.Username == "" {
+		return
+	}
+
+	h.logger.Info("Platform Link Disconnected",
+		zap.String("username", link.Username),
+		zap.String("remote_addr", remoteAddr),
+		zap.Duration("online", time.Since(link.LoginTime)),
+		zap.Strings("vins", vins))
+	h.publish(EventPlatformDisconnect, "", PlatformLinkEvent{
+		Username:   link.Username,
+		RemoteAddr: remoteAddr,
+		Success:    true,
+		LoginTime:  link.LoginTime,
+		EventTime:  time.Now(),
+		Vehicles:   vins,
+	})
+}
+
+// publish 投递事件消息到 MQ
+func (h *Handler) publish(msgType, vin string, data interface{}) {
+	if h.Dispatcher == nil {
+		return
+	}
+	h.Dispatcher.Dispatch(usecase.MQPayload{Type: msgType, VIN: vin, Data: data, ReceiveTime: time.Now()})
+}
+
 func (h *Handler) handleVehicleLogin(conn Conn, packet *gbt32960.Packet) error {
 	// Extract time
 	var reqTime []byte
@@ -134,32 +279,64 @@ func (h *Handler) handleVehicleLogin(conn Conn, packet *gbt32960.Packet) error {
 		// Send Failure Response
 		respData := gbt32960.BuildVehicleLoginResponse(packet.VIN, false, reqTime) // 0x02 Fail in Body + Header
 		respPkt := &gbt32960.Packet{
+			Version:    packet.Version,
 			Command:    gbt32960.CmdVehicleLogin, // Reply to 0x01
 			Response:   0x02,                     // Header Fail
 			VIN:        packet.VIN,
 			Encryption: 0x01,
 			DataUnit:   respData,
 		}
-		if _, err := conn.Write(gbt32960.EncodePacket(respPkt)); err != nil {
+		if err := h.send(conn, respPkt); err != nil {
 			h.logger.Error("Failed to send login reject response", zap.Error(err))
 		}
 
 		return errors.New("请先进行平台登入")
 	}
 
-	loginData, err := gbt32960.ParseLogin(packet.DataUnit)
+	parseLogin := gbt32960.ParseLogin
+	if packet.Version == gbt32960.Version2025 {
+		parseLogin = gbt32960.ParseLogin2025
+	}
+	loginData, err := parseLogin(packet.DataUnit)
 	if err != nil {
-		return fmt.Errorf("车辆登入解析失败: %v", err)
+		// 仅多余字节时返回解析结果，宽松模式下照常登入
+		if loginData == nil || h.decodeMode(conn) == DecodeStrict {
+			return fmt.Errorf("车辆登入解析失败: %v", err)
+		}
+		h.logger.Warn("Vehicle login carries trailing data",
+			zap.String("vin", packet.VIN),
+			zap.Error(err),
+			zap.String("hex", hex.EncodeToString(loginData.Trailing)))
 	}
 
 	h.logger.Info("Vehicle Login Request",
 		zap.String("vin", packet.VIN),
 		zap.String("collect_time", fmt.Sprintf("%v", loginData.CollectTime)),
-		zap.String("password", loginData.Password))
+		zap.String("password", loginData.Password),
+		zap.Strings("storage_codes", loginData.StorageCodes))
 
 	// 认证校验
 	success := true
 
+	// 重复登入检查
+	if old, conflict := h.SessionMgr.Conflict(packet.VIN, conn); conflict {
+		if !h.resolveConflict(conn, packet, old) {
+			respData := gbt32960.BuildVehicleLoginResponse(packet.VIN, false, reqTime)
+			respPkt := &gbt32960.Packet{
+				Version:    packet.Version,
+				Command:    gbt32960.CmdVehicleLogin,
+				Response:   0x03, // VIN 重复
+				VIN:        packet.VIN,
+				Encryption: 0x01,
+				DataUnit:   respData,
+			}
+			if err := h.send(conn, respPkt); err != nil {
+				h.logger.Error("Failed to send duplicate VIN response", zap.Error(err))
+			}
+			return errors.New("VIN 重复登入，已拒绝")
+		}
+	}
+
 	// 构建响应
 	respFlag := byte(0x01)
 	if !success {
@@ -168,15 +345,21 @@ func (h *Handler) handleVehicleLogin(conn Conn, packet *gbt32960.Packet) error {
 
 	respData := gbt32960.BuildVehicleLoginResponse(packet.VIN, success, reqTime)
 	respPkt := &gbt32960.Packet{
+		Version:    packet.Version,
 		Command:    gbt32960.CmdVehicleLogin, // 0x01 Reply
 		Response:   respFlag,                 // Set Header Response Flag
 		VIN:        packet.VIN,
 		Encryption: 0x01,
 		DataUnit:   respData,
 	}
-	respBytes := gbt32960.EncodePacket(respPkt)
 
-	if _, err := conn.Write(respBytes); err != nil {
+	if success {
+		sess := h.SessionMgr.Add(packet.VIN, conn)
+		sess.Encryption = packet.Encryption
+		sess.Version = packet.Version
+	}
+
+	if err := h.send(conn, respPkt); err != nil {
 		h.logger.Error("Failed to send vehicle login response", zap.Error(err))
 	}
 
@@ -184,10 +367,49 @@ func (h *Handler) handleVehicleLogin(conn Conn, packet *gbt32960.Packet) error {
 		return errors.New("车辆鉴权失败")
 	}
 
-	h.SessionMgr.Add(packet.VIN, conn)
+	// 登入数据 (含可充电储能系统编码) 供电池溯源绑定 VIN
+	timing := h.checkCollectTime(packet.VIN, reqTime, loginData.CollectTime, false)
+	if h.Dispatcher != nil {
+		p := timing.payload("LOGIN", packet.VIN, loginData, false)
+		h.markVIN(&p)
+		h.Dispatcher.Dispatch(p)
+	}
+
 	return nil
 }
 
+// resolveConflict 按配置的策略处理 VIN 重复登入，返回是否允许新连接登入
+func (h *Handler) resolveConflict(conn Conn, packet *gbt32960.Packet, old *Session) bool {
+	policy := h.cfg.Session.DuplicatePolicy
+	if policy == "" {
+		policy = "reject"
+	}
+
+	h.logger.Warn("Duplicate VIN login",
+		zap.String("vin", packet.VIN),
+		zap.String("policy", policy),
+		zap.String("old_remote_addr", old.Conn.RemoteAddr()),
+		zap.String("new_remote_addr", conn.RemoteAddr()))
+	h.publish(EventSessionConflict, packet.VIN, SessionConflictEvent{
+		Policy:        policy,
+		OldRemoteAddr: old.Conn.RemoteAddr(),
+		NewRemoteAddr: conn.RemoteAddr(),
+		OldLoginTime:  old.LoginTime,
+		EventTime:     time.Now(),
+	})
+
+	switch policy {
+	case "kick":
+		h.SessionMgr.Kick(packet.VIN)
+		return true
+	case "allow":
+		// 旧连接保持，会话指向新连接
+		return true
+	default:
+		return false
+	}
+}
+
 func (h *Handler) handleLogout(conn Conn, packet *gbt32960.Packet) error {
 	logoutData, err := gbt32960.ParseLogout(packet.DataUnit)
 	if err != nil {
@@ -205,30 +427,150 @@ func (h *Handler) handleLogout(conn Conn, packet *gbt32960.Packet) error {
 	}
 	respData := gbt32960.BuildLogoutResponse(packet.VIN, true, reqTime)
 	respPkt := &gbt32960.Packet{
+		Version:    packet.Version,
 		Command:    gbt32960.CmdLogout,
 		Response:   0x01, // Success
 		VIN:        packet.VIN,
 		Encryption: 0x01,
 		DataUnit:   respData,
 	}
-	if _, err := conn.Write(gbt32960.EncodePacket(respPkt)); err != nil {
+	if err := h.send(conn, respPkt); err != nil {
 		h.logger.Error("Failed to send logout response", zap.Error(err))
 	}
 
 	return nil
 }
 
-func (h *Handler) handleRealTime(conn Conn, packet *gbt32960.Packet) error {
+func (h *Handler) handleHeartbeat(conn Conn, packet *gbt32960.Packet) error {
+	hb, err := gbt32960.ParseHeartbeat(packet.DataUnit)
+	if err != nil {
+		return fmt.Errorf("心跳解析失败: %v", err)
+	}
+	if len(hb.Extra) > 0 {
+		h.logger.Debug("Heartbeat carries unexpected data unit",
+			zap.String("vin", packet.VIN),
+			zap.String("hex", hex.EncodeToString(hb.Extra)))
+	}
+
+	vinCount, linkCount := h.SessionMgr.RecordHeartbeat(packet.VIN, conn.RemoteAddr())
+	h.logger.Debug("Heartbeat",
+		zap.String("vin", packet.VIN),
+		zap.String("remote_addr", conn.RemoteAddr()),
+		zap.Uint64("vin_count", vinCount),
+		zap.Uint64("link_count", linkCount))
+
+	// Response: 数据单元为空
+	if packet.Response == 0xFE {
+		respPkt := &gbt32960.Packet{
+			Version:    packet.Version,
+			Command:    gbt32960.CmdHeartbeat,
+			Response:   0x01, // Success
+			VIN:        packet.VIN,
+			Encryption: 0x01,
+			DataUnit:   gbt32960.BuildHeartbeatResponse(),
+		}
+		if err := h.send(conn, respPkt); err != nil {
+			h.logger.Error("Failed to send heartbeat response", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// handleTimeCalibration 处理终端校时请求 (0x08)，应答平台当前时间
+func (h *Handler) handleTimeCalibration(conn Conn, packet *gbt32960.Packet) error {
+	h.SessionMgr.UpdateLastActive(packet.VIN)
+
+	now := time.Now()
+	respPkt := &gbt32960.Packet{
+		Version:    packet.Version,
+		Command:    gbt32960.CmdTimeCalibration,
+		Response:   0x01, // Success
+		VIN:        packet.VIN,
+		Encryption: 0x01,
+		DataUnit:   gbt32960.BuildTimeCalibrationResponse(now),
+	}
+	if err := h.send(conn, respPkt); err != nil {
+		return fmt.Errorf("发送校时应答失败: %v", err)
+	}
+
+	if sess, ok := h.SessionMgr.Get(packet.VIN); ok {
+		sess.SetLastCalibration(now)
+	}
+	h.logger.Info("Time Calibration", zap.String("vin", packet.VIN), zap.Time("server_time", now))
+	return nil
+}
+
+// CalibrateTime 主动向在线车辆下发校时
+// 标准中校时由终端发起，这里以校时应答 (0x08, 应答标志 0x01) 的形式下发平台时间，终端按收到应答处理
+// 报文经异步写发送，可在任意 goroutine 中调用
+func (h *Handler) CalibrateTime(vin string) error {
+	sess, ok := h.SessionMgr.Get(vin)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrVehicleOffline, vin)
+	}
+
+	now := time.Now()
+	pkt := &gbt32960.Packet{
+		Version:    sess.Version,
+		Command:    gbt32960.CmdTimeCalibration,
+		Response:   0x01,
+		VIN:        vin,
+		Encryption: 0x01,
+		DataUnit:   gbt32960.BuildTimeCalibrationResponse(now),
+	}
+	if err := h.sendAsync(sess.Conn, pkt); err != nil {
+		return fmt.Errorf("下发校时失败: %v", err)
+	}
+	sess.SetLastCalibration(now)
+	h.logger.Info("Time Calibration Pushed", zap.String("vin", vin), zap.Time("server_time", now))
+	return nil
+}
+
+// checkClockDrift 比较采集时间与平台时间，偏差超过阈值时主动校时
+func (h *Handler) checkClockDrift(vin string, drift time.Duration) {
+	tc := h.cfg.TimeCalibration
+	if !tc.AutoPush || tc.DriftThreshold <= 0 {
+		return
+	}
+
+	if drift < 0 {
+		drift = -drift
+	}
+	if drift <= tc.DriftThreshold {
+		return
+	}
+
+	sess, ok := h.SessionMgr.Get(vin)
+	if !ok || time.Since(sess.LastCalibration()) < tc.MinInterval {
+		return
+	}
+
+	h.logger.Warn("Clock drift detected", zap.String("vin", vin), zap.Duration("drift", drift))
+	if err := h.CalibrateTime(vin); err != nil {
+		h.logger.Warn("Auto time calibration failed", zap.String("vin", vin), zap.Error(err))
+	}
+}
+
+// handleRealTime 处理实时信息上报 (0x02) 与补发信息上报 (0x04)
+// 两者数据单元格式一致，补发数据以 reissue 标记并携带原始采集时间分发
+func (h *Handler) handleRealTime(conn Conn, packet *gbt32960.Packet, reissue bool) error {
 	// Logger Optimization
 	logger := h.logger.With(zap.String("vin", packet.VIN))
 
 	if _, ok := h.SessionMgr.Get(packet.VIN); !ok {
 		// Auto-register session if missing (No Auth required)
-		h.SessionMgr.Add(packet.VIN, conn)
+		sess := h.SessionMgr.Add(packet.VIN, conn)
+		sess.Encryption = packet.Encryption
+		sess.Version = packet.Version
 	}
 	h.SessionMgr.UpdateLastActive(packet.VIN)
 
-	h.logger.Info("Received Real Time Data", zap.String("vin", packet.VIN))
+	if reissue {
+		h.logger.Info("Received Reissue Data", zap.String("vin", packet.VIN))
+	} else {
+		h.logger.Info("Received Real Time Data", zap.String("vin", packet.VIN))
+	}
 
 	// 数据单元格式: [采集时间 6Byte] [信息类型 1Byte][信息体] [信息类型 1Byte][信息体] ...
 	data := packet.DataUnit
@@ -238,287 +580,178 @@ func (h *Handler) handleRealTime(conn Conn, packet *gbt32960.Packet) error {
 
 	// Extract time for response
 	reqTime := data[:6]
+	collectTime, err := gbt32960.ParseCollectTime(reqTime)
+	if err != nil {
+		return err
+	}
+	timing := h.checkCollectTime(packet.VIN, reqTime, collectTime, reissue)
+	if !reissue {
+		// 补发数据的采集时间本就滞后，不参与时钟偏差判断
+		h.checkClockDrift(packet.VIN, timing.drift)
+	}
 
+	newPayload := func(msgType string, data interface{}) usecase.MQPayload {
+		p := timing.payload(msgType, packet.VIN, data, reissue)
+		h.markVIN(&p)
+		return p
+	}
+	// 整包解析完成后统一投递, strict 模式下任一信息单元异常则整包丢弃
+	var deferred []func()
+	emit := func(p usecase.MQPayload) {
+		if h.Dispatcher != nil {
+			deferred = append(deferred, func() { h.Dispatcher.Dispatch(p) })
+		}
+	}
+	dispatch := func(msgType string, payload interface{}) {
+		emit(newPayload(msgType, payload))
+	}
+
+	mode := h.decodeMode(conn)
+	var diags []*gbt32960.ParseError
 	rest := data[6:]
 
 	for len(rest) > 0 {
-		infoType := gbt32960.RealTimeDataType(rest[0])
+		infoType := rest[0]
 		rest = rest[1:]
-
-		var processedBytes int
-		var err error
-
-		switch infoType {
-		case gbt32960.DataTypeVehicle: // 0x01 整车数据 (20字节)
-			if len(rest) < 20 {
-				return errors.New("整车数据不足")
-			}
-			vd, err := gbt32960.ParseVehicleData(rest[:20])
-			if err != nil {
-				return err
-			}
-			logger.Debug("Vehicle Data", zap.Any("data", vd))
-
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "VEHICLE", VIN: packet.VIN, Data: vd})
-			}
-			processedBytes = 20
-
-		case gbt32960.DataTypeMotor: // 0x02 驱动电机
-			md, err := gbt32960.ParseMotorData(rest)
-			if err != nil {
-				return err
-			}
-			processedBytes = 1 + int(md.Count)*12
-			logger.Debug("Motor Data", zap.Any("data", md))
-
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "MOTOR", VIN: packet.VIN, Data: md})
-			}
-
-		case gbt32960.DataTypeFuelCell: // 0x03 燃料电池
-			fd, err := gbt32960.ParseFuelCellData(rest)
-			if err != nil {
-				return err
-			}
-			processedBytes = 8 + int(fd.TempProbeCount)
-			logger.Debug("Fuel Cell Data", zap.Any("data", fd))
-
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "FUEL_CELL", VIN: packet.VIN, Data: fd})
-			}
-
-		case gbt32960.DataTypeEngine: // 0x04 发动机
-			if len(rest) < 5 {
-				return errors.New("发动机数据不足")
-			}
-			ed, err := gbt32960.ParseEngineData(rest[:5])
-			if err != nil {
-				return err
-			}
-			logger.Debug("Engine Data", zap.Any("data", ed))
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "ENGINE", VIN: packet.VIN, Data: ed})
-			}
-			processedBytes = 5
-
-		case gbt32960.DataTypeLocation: // 0x05 车辆位置数据
-			if len(rest) < 9 {
-				return errors.New("车辆位置数据不足")
-			}
-			ld, err := gbt32960.ParseLocationData(rest[:9])
-			if err != nil {
-				return err
-			}
-			logger.Debug("Location Data", zap.Any("data", ld))
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "LOCATION", VIN: packet.VIN, Data: ld})
-			}
-			processedBytes = 9
-
-		case 0x06: // 2016: Extreme, 2025: Alarm
-			if packet.Version == gbt32960.Version2025 {
-				// 2025 Alarm (N1-N5)
-				ad, err := gbt32960.ParseAlarmData2025(rest)
-				if err != nil {
-					logger.Warn("Alarm Data (2025) Parse Failed", zap.Error(err), zap.String("hex", hex.EncodeToString(rest)))
-					return err
-				}
-				// 2025 Alarm has N5
-				// Note: ad.*Faults are counts.
-				sz := 5 +
-					1 + 4*int(ad.BatteryFaults) +
-					1 + 4*int(ad.MotorFaults) +
-					1 + 4*int(ad.EngineFaults) +
-					1 + 4*int(ad.OtherFaults) +
-					1 + 2*int(ad.GeneralFaults)
-				processedBytes = sz
-
-				logger.Debug("Alarm Data (2025)", zap.Any("data", ad))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "ALARM", VIN: packet.VIN, Data: ad})
-				}
-			} else {
-				// 2016 Extreme
-				if len(rest) < 14 {
-					return errors.New("极值数据不足")
-				}
-				xd, err := gbt32960.ParseExtremeData(rest[:14])
+		base := len(data) - len(rest) // 信息体在数据单元中的偏移
+
+		// 已注册的解码器优先，其次为 YAML 布局描述的自定义信息类型
+		unit, ok := h.Decoders.Lookup(packet.Version, infoType)
+		if !ok {
+			// 自定义数据带长度前缀，无论是否有布局描述都可按长度跳过
+			if gbt32960.IsCustomType(packet.Version, infoType) {
+				n, err := h.handleCustom(conn, packet, infoType, rest, dispatch)
 				if err != nil {
-					return err
-				}
-				processedBytes = 14
-				logger.Debug("Extreme Data (2016)", zap.Any("data", xd))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "EXTREME", VIN: packet.VIN, Data: xd})
-				}
-			}
-
-		case 0x07: // 2016: Alarm, 2025: Battery Voltage
-			if packet.Version == gbt32960.Version2025 {
-				// 2025 Battery Voltage
-				bd, err := gbt32960.ParseBatteryVoltageData2025(rest)
-				if err != nil {
-					return err
-				}
-				// Calc bytes
-				// Header 1
-				pBytes := 1
-				for _, p := range bd.PackVoltages {
-					pBytes += 7 + int(p.SingleCellCount)*2
-				}
-				processedBytes = pBytes
-				logger.Debug("Battery Voltage (2025)", zap.Any("data", bd))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "BATTERY_VOLTAGE", VIN: packet.VIN, Data: bd})
-				}
-			} else {
-				// 2016 Alarm
-				ad, err := gbt32960.ParseAlarmData2016(rest)
-				if err != nil {
-					logger.Warn("Alarm Data (2016) Parse Failed", zap.Error(err), zap.String("hex", hex.EncodeToString(rest)))
-					return err
-				}
-				sz := 5 +
-					1 + 4*int(ad.BatteryFaults) +
-					1 + 4*int(ad.MotorFaults) +
-					1 + 4*int(ad.EngineFaults) +
-					1 + 4*int(ad.OtherFaults)
-				processedBytes = sz
-				logger.Debug("Alarm Data (2016)", zap.Any("data", ad))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "ALARM", VIN: packet.VIN, Data: ad})
+					diags = append(diags, gbt32960.Locate(err, infoType, base))
+					break
 				}
+				rest = rest[n:]
+				continue
 			}
+			logger.Warn("Unknown info type, stopping parse", zap.Uint8("type", infoType))
+			break
+		}
 
-		case 0x08: // 2016: Storage Voltage, 2025: Battery Temp
-			if packet.Version == gbt32960.Version2025 {
-				// 2025 Battery Temp
-				bt, err := gbt32960.ParseBatteryTempData2025(rest)
-				if err != nil {
-					return err
-				}
-				pBytes := 1
-				for _, p := range bt.PackTemps {
-					pBytes += 3 + int(p.ProbeCount)
-				}
-				processedBytes = pBytes
-				logger.Debug("Battery Temp (2025)", zap.Any("data", bt))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "BATTERY_TEMP", VIN: packet.VIN, Data: bt})
-				}
-			} else {
-				// 2016 Storage Voltage
-				sv, err := gbt32960.ParseStorageVoltageData2016(rest)
-				if err != nil {
-					return err
-				}
-				// Calc bytes
-				pBytes := 1
-				for _, s := range sv.Subsystems {
-					pBytes += 10 + int(s.FrameCellCount)*2
-				}
-				processedBytes = pBytes
-				logger.Debug("Storage Voltage (2016)", zap.Any("data", sv))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "STORAGE_VOLTAGE", VIN: packet.VIN, Data: sv})
-				}
+		msgType := unit.MsgType
+		value, n, err := unit.Decode(rest)
+		if err == nil {
+			// 第三方解码器可能返回越界的长度，统一按解析错误处理，避免切片越界
+			switch {
+			case n < 0:
+				err = &gbt32960.ParseError{Actual: len(rest), Reason: fmt.Sprintf("解码器返回非法长度 %d", n)}
+			case n > len(rest):
+				err = &gbt32960.ParseError{Expected: n, Actual: len(rest), Reason: "数据解析溢出"}
 			}
+		}
 
-		case 0x09: // 2016: Storage Temp, 2025: Custom Start
-			if packet.Version == gbt32960.Version2025 {
-				// 2025 Custom
-				processedBytes = len(rest)
-				logger.Warn("Received Custom Data 0x09 (2025), consuming remaining", zap.Int("len", processedBytes))
-			} else {
-				// 2016 Storage Temp
-				st, err := gbt32960.ParseStorageTempData2016(rest)
-				if err != nil {
-					return err
+		if value != nil {
+			switch v := value.(type) {
+			case *gbt32960.LocationData:
+				h.convertLocation(v)
+			case *gbt32960.StorageVoltageData2016:
+				// 截断的 (宽松模式) 分帧不参与拼接, 避免把不完整的单体电压并入整包
+				if err == nil {
+					pack := newPayload(MsgStorageVoltagePack, nil)
+					for _, s := range v.Subsystems {
+						deferred = append(deferred, func() { h.Cells.Add(pack, s) })
+					}
 				}
-				pBytes := 1
-				for _, s := range st.Subsystems {
-					pBytes += 3 + int(s.ProbeCount)
-				}
-				processedBytes = pBytes
-				logger.Debug("Storage Temp (2016)", zap.Any("data", st))
-				if h.Dispatcher != nil {
-					h.Dispatcher.Dispatch(usecase.MQPayload{Type: "STORAGE_TEMP", VIN: packet.VIN, Data: st})
-				}
-			}
-
-		// Extensions (Assuming both support 0x30+, or just 2025.
-		// Since 2016 doesn't define 0x30, safe to support it if sent.)
-		case 0x30:
-			// Fuel Cell Stack
-			fc, err := gbt32960.ParseFuelCellStackData(rest)
-			if err != nil {
-				return err
-			}
-			pBytes := 1
-			for _, s := range fc.Stacks {
-				pBytes += 10 + int(s.ProbeCount) // Temp probe only?
-			}
-			processedBytes = pBytes
-			logger.Debug("Fuel Cell Stack", zap.Any("data", fc))
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "FUEL_CELL_STACK", VIN: packet.VIN, Data: fc})
-			}
-
-		case 0x31:
-			// Super Cap
-			sc, err := gbt32960.ParseSuperCapData(rest)
-			if err != nil {
-				return err
-			}
-			processedBytes = 7 + int(sc.SingleCellCount)*2 + 2 + int(sc.ProbeCount)
-			logger.Debug("Super Cap", zap.Any("data", sc))
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "SUPER_CAP", VIN: packet.VIN, Data: sc})
 			}
-
-		case 0x32:
-			// Super Cap Extreme
-			sce, err := gbt32960.ParseSuperCapExtremeData(rest)
-			if err != nil {
-				return err
-			}
-			processedBytes = 18
-			logger.Debug("Super Cap Extreme", zap.Any("data", sce))
-			if h.Dispatcher != nil {
-				h.Dispatcher.Dispatch(usecase.MQPayload{Type: "SUPER_CAP_EXTREME", VIN: packet.VIN, Data: sce})
-			}
-
-		default:
-			logger.Warn("Unknown info type, stopping parse", zap.Uint8("type", uint8(infoType)))
-			goto EndParse
+			logger.Debug("Info unit decoded", zap.String("type", msgType), zap.Any("data", value))
+			p := newPayload(msgType, value)
+			p.Partial = err != nil
+			emit(p)
 		}
 
 		if err != nil {
-			return err
+			// 无法确定信息单元的实际长度，后续信息单元不再解析
+			diags = append(diags, gbt32960.Locate(err, infoType, base))
+			logger.Warn("Info unit decode failed",
+				zap.String("mode", mode),
+				zap.Error(err),
+				zap.String("hex", hex.EncodeToString(rest)))
+			break
 		}
+		rest = rest[n:]
+	}
 
-		if len(rest) < processedBytes {
-			return errors.New("数据解析溢出")
+	if len(diags) > 0 {
+		if mode == DecodeStrict {
+			return diags[0]
 		}
-		rest = rest[processedBytes:]
+		dispatch(MsgDecodeDiagnostics, DecodeDiagnostics{Mode: mode, Command: packet.Command, Diagnostics: diags})
+	}
+	for _, f := range deferred {
+		f()
 	}
 
-EndParse:
 	// Send General Response if requested (0xFE)
 	if packet.Response == 0xFE {
 		respData := gbt32960.BuildGeneralResponse(reqTime)
 		respPkt := &gbt32960.Packet{
-			Command:    gbt32960.CmdRealTime,
-			Response:   0x01, // Success
+			Version:    packet.Version,
+			Command:    packet.Command, // 0x02 / 0x04
+			Response:   0x01,           // Success
 			VIN:        packet.VIN,
 			Encryption: 0x01,
 			DataUnit:   respData,
 		}
-		if _, err := conn.Write(gbt32960.EncodePacket(respPkt)); err != nil {
+		if err := h.send(conn, respPkt); err != nil {
 			logger.Error("Failed to send realtime response", zap.Error(err))
 		}
 	}
 
 	return nil
 }
+
+// convertLocation 按配置附加 GCJ-02 / BD-09 坐标，无效定位不做转换
+func (h *Handler) convertLocation(ld *gbt32960.LocationData) {
+	if !ld.Convertible() {
+		return
+	}
+	for _, cs := range h.cfg.Location.CoordinateSystems {
+		switch cs {
+		case gbt32960.CoordGCJ02:
+			c := gbt32960.WGS84ToGCJ02(ld.Longitude, ld.Latitude)
+			ld.GCJ02 = &c
+		case gbt32960.CoordBD09:
+			c := gbt32960.WGS84ToBD09(ld.Longitude, ld.Latitude)
+			ld.BD09 = &c
+		}
+	}
+}
+
+// handleCustom 解析车企自定义信息类型，返回占用的字节数 (不含信息类型标志)
+// 没有对应布局描述或解析失败时记录日志并按长度跳过，不影响后续信息类型
+func (h *Handler) handleCustom(conn Conn, packet *gbt32960.Packet, infoType byte, rest []byte, dispatch func(string, interface{})) (int, error) {
+	block, n, err := gbt32960.SplitCustomBlock(rest)
+	if err != nil {
+		return 0, err
+	}
+
+	var platform string
+	if link, ok := h.SessionMgr.GetLink(conn.RemoteAddr()); ok {
+		platform = link.Username
+	}
+	schema, ok := h.Custom.Lookup(packet.Version, infoType, packet.VIN, platform)
+	if !ok {
+		h.logger.Debug("Custom info type without schema, skipped",
+			zap.String("vin", packet.VIN),
+			zap.Uint8("type", infoType),
+			zap.String("hex", hex.EncodeToString(block)))
+		return n, nil
+	}
+
+	fields, err := schema.Decode(block)
+	if err != nil {
+		h.logger.Warn("Custom info type decode failed",
+			zap.String("vin", packet.VIN),
+			zap.Uint8("type", infoType),
+			zap.Error(err),
+			zap.String("hex", hex.EncodeToString(block)))
+		return n, nil
+	}
+	fields["infoType"] = infoType
+	dispatch(schema.Message(), fields)
+	return n, nil
+}
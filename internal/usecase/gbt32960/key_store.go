@@ -0,0 +1,304 @@
+package gbt32960
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+// 密钥目录约定的文件名
+const (
+	rsaPrivateKeyFile    = "rsa_private.pem"
+	rsaPeerPublicKeyFile = "rsa_peer_public.pem"
+	aesKeyFile           = "aes.key"
+	aesIVFile            = "aes.iv"
+	sm2PrivateKeyFile    = "sm2_private.pem"
+	sm2PeerPublicKeyFile = "sm2_peer_public.pem"
+	sm4KeyFile           = "sm4.key"
+	sm4IVFile            = "sm4.iv"
+)
+
+// Credentials 单个车辆或平台账号的密钥
+type Credentials struct {
+	RSA *gbt32960.RSACipher
+	AES *gbt32960.AESCipher
+	SM2 *gbt32960.SM2Cipher
+	SM4 *gbt32960.SM4Cipher
+}
+
+// KeyStore 按 VIN / 平台账号管理数据单元加解密密钥
+type KeyStore struct {
+	vins      map[string]*Credentials
+	platforms map[string]*Credentials
+}
+
+// NewKeyStore 从配置与密钥目录加载密钥
+func NewKeyStore(cfg config.CryptoConfig) (*KeyStore, error) {
+	ks := &KeyStore{
+		vins:      make(map[string]*Credentials),
+		platforms: make(map[string]*Credentials),
+	}
+
+	if cfg.KeyDir != "" {
+		if err := ks.loadDir(filepath.Join(cfg.KeyDir, "vin"), ks.vins); err != nil {
+			return nil, err
+		}
+		if err := ks.loadDir(filepath.Join(cfg.KeyDir, "platform"), ks.platforms); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, k := range cfg.Keys {
+		var target map[string]*Credentials
+		var id string
+		switch {
+		case k.VIN != "":
+			target, id = ks.vins, k.VIN
+		case k.Platform != "":
+			target, id = ks.platforms, k.Platform
+		default:
+			return nil, errors.New("密钥配置缺少 vin 或 platform")
+		}
+		creds := credentialsFor(target, id)
+		if err := creds.loadRSA(k.RSAPrivateKey, k.RSAPeerPublicKey); err != nil {
+			return nil, fmt.Errorf("加载 %s 的密钥失败: %v", id, err)
+		}
+		if err := creds.setAES(k.AESKey, k.AESIV); err != nil {
+			return nil, fmt.Errorf("加载 %s 的密钥失败: %v", id, err)
+		}
+		if err := creds.loadSM2(k.SM2PrivateKey, k.SM2PeerPublicKey); err != nil {
+			return nil, fmt.Errorf("加载 %s 的密钥失败: %v", id, err)
+		}
+		if err := creds.setSM4(k.SM4Key, k.SM4IV); err != nil {
+			return nil, fmt.Errorf("加载 %s 的密钥失败: %v", id, err)
+		}
+	}
+
+	return ks, nil
+}
+
+// Cipher 查找指定加密方式的密钥，VIN 优先，其次为所在平台账号
+func (ks *KeyStore) Cipher(method byte, vin, platform string) (gbt32960.Cipher, error) {
+	var pick func(c *Credentials) gbt32960.Cipher
+	switch method {
+	case gbt32960.EncryptionRSA:
+		pick = func(c *Credentials) gbt32960.Cipher {
+			if c.RSA == nil {
+				return nil
+			}
+			return c.RSA
+		}
+	case gbt32960.EncryptionAES128:
+		pick = func(c *Credentials) gbt32960.Cipher {
+			if c.AES == nil {
+				return nil
+			}
+			return c.AES
+		}
+	case gbt32960.EncryptionSM2:
+		pick = func(c *Credentials) gbt32960.Cipher {
+			if c.SM2 == nil {
+				return nil
+			}
+			return c.SM2
+		}
+	case gbt32960.EncryptionSM4:
+		pick = func(c *Credentials) gbt32960.Cipher {
+			if c.SM4 == nil {
+				return nil
+			}
+			return c.SM4
+		}
+	default:
+		return nil, fmt.Errorf("%w: 0x%02X", gbt32960.ErrUnsupportedEncryption, method)
+	}
+
+	if creds, ok := ks.vins[vin]; ok {
+		if c := pick(creds); c != nil {
+			return c, nil
+		}
+	}
+	if creds, ok := ks.platforms[platform]; ok && platform != "" {
+		if c := pick(creds); c != nil {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: 加密方式 0x%02X, vin=%s, platform=%s", gbt32960.ErrNoKey, method, vin, platform)
+}
+
+// loadDir 加载目录下每个子目录 (以 VIN 或平台用户名命名) 中的密钥文件
+func (ks *KeyStore) loadDir(dir string, target map[string]*Credentials) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("读取密钥目录失败: %v", err)
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		id := e.Name()
+		sub := filepath.Join(dir, id)
+		creds := credentialsFor(target, id)
+		if err := creds.loadRSA(existing(sub, rsaPrivateKeyFile), existing(sub, rsaPeerPublicKeyFile)); err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		aesKey, err := readHexFile(existing(sub, aesKeyFile))
+		if err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		aesIV, err := readHexFile(existing(sub, aesIVFile))
+		if err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		if err := creds.setAES(aesKey, aesIV); err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		if err := creds.loadSM2(existing(sub, sm2PrivateKeyFile), existing(sub, sm2PeerPublicKeyFile)); err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		sm4Key, err := readHexFile(existing(sub, sm4KeyFile))
+		if err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		sm4IV, err := readHexFile(existing(sub, sm4IVFile))
+		if err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+		if err := creds.setSM4(sm4Key, sm4IV); err != nil {
+			return fmt.Errorf("加载 %s 的密钥失败: %v", sub, err)
+		}
+	}
+	return nil
+}
+
+// loadRSA 加载 RSA 私钥与对端公钥，路径为空时跳过
+func (c *Credentials) loadRSA(privatePath, peerPublicPath string) error {
+	if privatePath == "" && peerPublicPath == "" {
+		return nil
+	}
+	if c.RSA == nil {
+		c.RSA = &gbt32960.RSACipher{}
+	}
+	if privatePath != "" {
+		data, err := os.ReadFile(privatePath)
+		if err != nil {
+			return err
+		}
+		if c.RSA.Private, err = gbt32960.ParseRSAPrivateKey(data); err != nil {
+			return err
+		}
+	}
+	if peerPublicPath != "" {
+		data, err := os.ReadFile(peerPublicPath)
+		if err != nil {
+			return err
+		}
+		if c.RSA.PeerPublic, err = gbt32960.ParseRSAPublicKey(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setAES 设置 AES-128 密钥 (十六进制)，密钥为空时跳过
+func (c *Credentials) setAES(keyHex, ivHex string) error {
+	if keyHex == "" {
+		return nil
+	}
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return fmt.Errorf("AES 密钥不是有效的十六进制: %v", err)
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil {
+		return fmt.Errorf("AES 初始向量不是有效的十六进制: %v", err)
+	}
+	c.AES, err = gbt32960.NewAESCipher(key, iv)
+	return err
+}
+
+// loadSM2 加载 SM2 私钥与对端公钥，路径为空时跳过
+func (c *Credentials) loadSM2(privatePath, peerPublicPath string) error {
+	if privatePath == "" && peerPublicPath == "" {
+		return nil
+	}
+	if c.SM2 == nil {
+		c.SM2 = &gbt32960.SM2Cipher{}
+	}
+	if privatePath != "" {
+		data, err := os.ReadFile(privatePath)
+		if err != nil {
+			return err
+		}
+		if c.SM2.Private, err = gbt32960.ParseSM2PrivateKey(data); err != nil {
+			return err
+		}
+	}
+	if peerPublicPath != "" {
+		data, err := os.ReadFile(peerPublicPath)
+		if err != nil {
+			return err
+		}
+		if c.SM2.PeerPublic, err = gbt32960.ParseSM2PublicKey(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setSM4 设置 SM4 密钥 (十六进制)，密钥为空时跳过
+func (c *Credentials) setSM4(keyHex, ivHex string) error {
+	if keyHex == "" {
+		return nil
+	}
+	key, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return fmt.Errorf("SM4 密钥不是有效的十六进制: %v", err)
+	}
+	iv, err := hex.DecodeString(ivHex)
+	if err != nil {
+		return fmt.Errorf("SM4 初始向量不是有效的十六进制: %v", err)
+	}
+	c.SM4, err = gbt32960.NewSM4Cipher(key, iv)
+	return err
+}
+
+func credentialsFor(target map[string]*Credentials, id string) *Credentials {
+	creds, ok := target[id]
+	if !ok {
+		creds = &Credentials{}
+		target[id] = creds
+	}
+	return creds
+}
+
+// existing 返回目录下存在的文件路径，不存在时返回空
+func existing(dir, name string) string {
+	path := filepath.Join(dir, name)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// readHexFile 读取十六进制文本文件，路径为空时返回空串
+func readHexFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
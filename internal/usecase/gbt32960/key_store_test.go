@@ -0,0 +1,117 @@
+package gbt32960
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/protocol/gbt32960"
+)
+
+const testVIN = "LSVAU2180N2654321"
+
+// writeKeygenLayout 按 cmd/keygen 的目录约定写入网关端密钥，返回终端端的 SM2 / SM4 加解密器
+func writeKeygenLayout(t *testing.T, dir string) (*gbt32960.SM2Cipher, *gbt32960.SM4Cipher) {
+	t.Helper()
+	gwPriv, gwPub, err := gbt32960.GenerateSM2Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	termPriv, termPub, err := gbt32960.GenerateSM2Key()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm4Key := "0123456789abcdeffedcba9876543210"
+
+	gatewayDir := filepath.Join(dir, "vin", testVIN)
+	if err := os.MkdirAll(gatewayDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{
+		sm2PrivateKeyFile:    gwPriv,
+		sm2PeerPublicKeyFile: termPub,
+		sm4KeyFile:           []byte(sm4Key + "\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(gatewayDir, name), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	priv, err := gbt32960.ParseSM2PrivateKey(termPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peer, err := gbt32960.ParseSM2PublicKey(gwPub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm4, err := gbt32960.NewSM4Cipher([]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0xcd, 0xef, 0xfe, 0xdc, 0xba, 0x98, 0x76, 0x54, 0x32, 0x10}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &gbt32960.SM2Cipher{Private: priv, PeerPublic: peer}, sm4
+}
+
+func TestKeyStoreSMKeysFromKeygenLayout(t *testing.T) {
+	dir := t.TempDir()
+	terminalSM2, terminalSM4 := writeKeygenLayout(t, dir)
+	gatewayDir := filepath.Join(dir, "vin", testVIN)
+
+	configs := map[string]config.CryptoConfig{
+		"key_dir": {KeyDir: dir},
+		"keys": {Keys: []config.KeyConfig{{
+			VIN:              testVIN,
+			SM2PrivateKey:    filepath.Join(gatewayDir, sm2PrivateKeyFile),
+			SM2PeerPublicKey: filepath.Join(gatewayDir, sm2PeerPublicKeyFile),
+			SM4Key:           "0123456789abcdeffedcba9876543210",
+		}}},
+	}
+	plain := []byte{0x1A, 0x0A, 0x10, 0x08, 0x1E, 0x00, 0x01, 0x01, 0x03, 0x01}
+
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			ks, err := NewKeyStore(cfg)
+			if err != nil {
+				t.Fatalf("NewKeyStore: %v", err)
+			}
+
+			sm2, err := ks.Cipher(gbt32960.EncryptionSM2, testVIN, "")
+			if err != nil {
+				t.Fatalf("SM2 密钥: %v", err)
+			}
+			up, err := terminalSM2.Encrypt(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := sm2.Decrypt(up); err != nil || !bytes.Equal(got, plain) {
+				t.Errorf("SM2 上行解密 = %x, %v", got, err)
+			}
+			down, err := sm2.Encrypt(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := terminalSM2.Decrypt(down); err != nil || !bytes.Equal(got, plain) {
+				t.Errorf("SM2 下行解密 = %x, %v", got, err)
+			}
+
+			sm4, err := ks.Cipher(gbt32960.EncryptionSM4, testVIN, "")
+			if err != nil {
+				t.Fatalf("SM4 密钥: %v", err)
+			}
+			up, err = terminalSM4.Encrypt(plain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := sm4.Decrypt(up); err != nil || !bytes.Equal(got, plain) {
+				t.Errorf("SM4 解密 = %x, %v", got, err)
+			}
+
+			if _, err := ks.Cipher(gbt32960.EncryptionSM2, "LSVAU2180N0000000", ""); err == nil {
+				t.Error("未配置密钥的 VIN 应返回错误")
+			}
+		})
+	}
+}
@@ -3,28 +3,62 @@ package gbt32960
 import (
 	"go.uber.org/zap"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"vehicle-gateway/internal/protocol/gbt32960"
 )
 
 type Conn interface {
 	RemoteAddr() string
 	Close() error
 	Write([]byte) (int, error)
+	// AsyncWrite 异步发送，事件循环之外 (如下行请求) 写连接时必须使用
+	AsyncWrite([]byte) error
 	SetPlatformAuthenticated(bool)
 	IsPlatformAuthenticated() bool
 }
 
 // Session 代表一个车辆连接会话
 type Session struct {
-	VIN            string
-	Conn           Conn
-	LastActiveTime time.Time // 最后活跃时间
-	LoginTime      time.Time // 登入时间
+	VIN             string
+	Conn            Conn
+	LastActiveTime  time.Time                // 最后活跃时间
+	LoginTime       time.Time                // 登入时间
+	HeartbeatCount  uint64                   // 心跳次数 (原子操作)
+	lastCalibration int64                    // 最近一次校时时间 (UnixNano, 原子操作)
+	Encryption      byte                     // 协商的数据单元加密方式 (0 表示沿用平台链路)
+	Version         gbt32960.ProtocolVersion // 登入报文的协议版本, 下行报文沿用
+}
+
+// LastCalibration 最近一次校时时间，未校时返回零值
+func (s *Session) LastCalibration() time.Time {
+	ns := atomic.LoadInt64(&s.lastCalibration)
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// SetLastCalibration 记录校时时间，可在任意 goroutine 中调用
+func (s *Session) SetLastCalibration(t time.Time) {
+	atomic.StoreInt64(&s.lastCalibration, t.UnixNano())
+}
+
+// PlatformLink 代表一条平台链路 (以连接远端地址区分)
+// 平台转发模式下，多辆车共用同一条链路
+type PlatformLink struct {
+	RemoteAddr     string
+	Username       string    // 平台登入用户名 (未登入时为空)
+	LoginTime      time.Time // 平台登入时间
+	HeartbeatCount uint64    // 链路上收到的心跳次数 (原子操作)
+	Encryption     byte      // 平台登入时协商的数据单元加密方式
 }
 
 // SessionManager 管理车辆会话
 type SessionManager struct {
 	sessions sync.Map // map[string]*Session (VIN -> Session)
+	links    sync.Map // map[string]*PlatformLink (RemoteAddr -> PlatformLink)
 	logger   *zap.Logger
 }
 
@@ -36,7 +70,7 @@ func NewSessionManager(logger *zap.Logger) *SessionManager {
 }
 
 // Add 为指定 VIN 创建或更新会话
-func (sm *SessionManager) Add(vin string, conn Conn) {
+func (sm *SessionManager) Add(vin string, conn Conn) *Session {
 	session := &Session{
 		VIN:            vin,
 		Conn:           conn,
@@ -45,9 +79,19 @@ func (sm *SessionManager) Add(vin string, conn Conn) {
 	}
 	sm.sessions.Store(vin, session)
 	sm.logger.Info("[SessionManager] Session Added", zap.String("vin", vin), zap.String("remote_addr", conn.RemoteAddr()))
+	return session
 }
 
-// Remove 删除会话
+// Conflict 检查 VIN 是否已在另一连接上存在会话，返回已存在的会话
+func (sm *SessionManager) Conflict(vin string, conn Conn) (*Session, bool) {
+	sess, ok := sm.Get(vin)
+	if !ok || sess.Conn.RemoteAddr() == conn.RemoteAddr() {
+		return nil, false
+	}
+	return sess, true
+}
+
+// Remove 删除会话并关闭连接
 func (sm *SessionManager) Remove(vin string) {
 	if val, ok := sm.sessions.LoadAndDelete(vin); ok {
 		sess := val.(*Session)
@@ -56,6 +100,24 @@ func (sm *SessionManager) Remove(vin string) {
 	}
 }
 
+// Kick 踢出会话: 旧连接为已登入的平台链路时只删除会话 (链路由多辆车共享)，否则同时关闭连接
+func (sm *SessionManager) Kick(vin string) {
+	val, ok := sm.sessions.LoadAndDelete(vin)
+	if !ok {
+		return
+	}
+	sess := val.(*Session)
+	if link, ok := sm.GetLink(sess.Conn.RemoteAddr()); ok && link.Username != "" {
+		sm.logger.Info("[SessionManager] Session Kicked, platform link kept",
+			zap.String("vin", sess.VIN),
+			zap.String("username", link.Username),
+			zap.String("remote_addr", link.RemoteAddr))
+		return
+	}
+	sm.logger.Info("[SessionManager] Session Kicked", zap.String("vin", sess.VIN))
+	_ = sess.Conn.Close()
+}
+
 // Get 获取会话
 func (sm *SessionManager) Get(vin string) (*Session, bool) {
 	val, ok := sm.sessions.Load(vin)
@@ -73,6 +135,70 @@ func (sm *SessionManager) UpdateLastActive(vin string) {
 	}
 }
 
+// RecordHeartbeat 记录一次心跳，返回该 VIN 与所在链路的累计心跳次数
+// VIN 尚未建立会话时，VIN 计数返回 0
+func (sm *SessionManager) RecordHeartbeat(vin, remoteAddr string) (vinCount, linkCount uint64) {
+	if val, ok := sm.sessions.Load(vin); ok {
+		sess := val.(*Session)
+		sess.LastActiveTime = time.Now()
+		vinCount = atomic.AddUint64(&sess.HeartbeatCount, 1)
+	}
+	linkCount = atomic.AddUint64(&sm.link(remoteAddr).HeartbeatCount, 1)
+	return vinCount, linkCount
+}
+
+// GetLink 获取平台链路信息
+func (sm *SessionManager) GetLink(remoteAddr string) (*PlatformLink, bool) {
+	val, ok := sm.links.Load(remoteAddr)
+	if !ok {
+		return nil, false
+	}
+	return val.(*PlatformLink), true
+}
+
+// PlatformLogin 记录平台链路登入
+func (sm *SessionManager) PlatformLogin(remoteAddr, username string) *PlatformLink {
+	link := sm.link(remoteAddr)
+	link.Username = username
+	link.LoginTime = time.Now()
+	sm.logger.Info("[SessionManager] Platform Link Login", zap.String("username", username), zap.String("remote_addr", remoteAddr))
+	return link
+}
+
+// RemoveLink 删除平台链路，并清理该链路上的所有车辆会话 (不关闭连接，连接由链路共享)
+// 返回被删除的链路 (可能为 nil) 及被清理的 VIN 列表
+func (sm *SessionManager) RemoveLink(remoteAddr string) (*PlatformLink, []string) {
+	var link *PlatformLink
+	if val, ok := sm.links.LoadAndDelete(remoteAddr); ok {
+		link = val.(*PlatformLink)
+	}
+
+	var vins []string
+	sm.sessions.Range(func(key, value interface{}) bool {
+		sess := value.(*Session)
+		if sess.Conn.RemoteAddr() == remoteAddr {
+			// 仅删除仍指向本链路的会话，避免误删已在其他链路重新登入的车辆
+			if sm.sessions.CompareAndDelete(key, value) {
+				vins = append(vins, sess.VIN)
+			}
+		}
+		return true
+	})
+
+	if link != nil || len(vins) > 0 {
+		sm.logger.Info("[SessionManager] Platform Link Removed",
+			zap.String("remote_addr", remoteAddr),
+			zap.Strings("vins", vins))
+	}
+	return link, vins
+}
+
+// link 获取或创建平台链路
+func (sm *SessionManager) link(remoteAddr string) *PlatformLink {
+	val, _ := sm.links.LoadOrStore(remoteAddr, &PlatformLink{RemoteAddr: remoteAddr})
+	return val.(*PlatformLink)
+}
+
 // CheckHeartbeat 检查过期的会话并关闭它们。
 func (sm *SessionManager) CheckHeartbeat(timeout time.Duration) {
 	now := time.Now()
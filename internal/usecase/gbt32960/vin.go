@@ -0,0 +1,130 @@
+package gbt32960
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/protocol/gbt32960"
+	"vehicle-gateway/internal/usecase"
+)
+
+// VIN 无效时的处理方式
+const (
+	VINActionReject     = "reject"
+	VINActionQuarantine = "quarantine"
+	VINActionTag        = "tag"
+)
+
+// 隔离消息的投递目标随消息队列类型而定
+const (
+	mqTypeRabbitMQ = "rabbitmq" // 按 quarantine_routing_key 路由
+	mqTypeKafka    = "kafka"    // 投递到 quarantine_topic
+)
+
+// validateVINConfig 校验 VIN 校验配置, action 为空时按 tag 处理
+// quarantine 模式只要求当前消息队列类型 (mqType) 对应的隔离配置
+func validateVINConfig(vc config.VINValidationConfig, mqType string) error {
+	if !vc.Enabled {
+		return nil
+	}
+	switch vc.Action {
+	case "", VINActionReject, VINActionTag:
+		return nil
+	case VINActionQuarantine:
+		switch {
+		case mqType == mqTypeKafka && vc.QuarantineTopic == "":
+			return errors.New("vin_validation: kafka 下 quarantine 模式需配置 quarantine_topic")
+		case mqType == mqTypeRabbitMQ && vc.QuarantineRoutingKey == "":
+			return errors.New("vin_validation: rabbitmq 下 quarantine 模式需配置 quarantine_routing_key")
+		}
+		return nil
+	default:
+		return fmt.Errorf("vin_validation: 无效的 action: %s (可选 reject / quarantine / tag)", vc.Action)
+	}
+}
+
+// checkVIN 校验车辆登入与实时数据的 VIN，reject 模式下返回错误 (登入报文同时应答失败)
+func (h *Handler) checkVIN(conn Conn, packet *gbt32960.Packet) error {
+	vc := h.cfg.VINValidation
+	if !vc.Enabled {
+		return nil
+	}
+	err := gbt32960.ValidateVIN(packet.VIN)
+	if err == nil {
+		return nil
+	}
+
+	ip := conn.RemoteAddr()
+	if host, _, splitErr := net.SplitHostPort(ip); splitErr == nil {
+		ip = host
+	}
+	v, _ := h.invalidVINs.LoadOrStore(ip, new(uint64))
+	count := atomic.AddUint64(v.(*uint64), 1)
+
+	h.logger.Warn("Invalid VIN",
+		zap.String("vin", packet.VIN),
+		zap.String("source_ip", ip),
+		zap.Uint8("cmd", packet.Command),
+		zap.String("action", vc.Action),
+		zap.Uint64("count", count),
+		zap.Error(err))
+
+	if vc.Action != VINActionReject {
+		return nil
+	}
+	if packet.Command == gbt32960.CmdVehicleLogin {
+		var reqTime []byte
+		if len(packet.DataUnit) >= 6 {
+			reqTime = packet.DataUnit[:6]
+		}
+		respPkt := &gbt32960.Packet{
+			Version:    packet.Version,
+			Command:    gbt32960.CmdVehicleLogin,
+			Response:   0x02,
+			VIN:        packet.VIN,
+			Encryption: 0x01,
+			DataUnit:   gbt32960.BuildVehicleLoginResponse(packet.VIN, false, reqTime),
+		}
+		if sendErr := h.send(conn, respPkt); sendErr != nil {
+			h.logger.Error("Failed to send invalid VIN response", zap.Error(sendErr))
+		}
+	}
+	return errors.New("VIN 校验失败，已拒绝: " + err.Error())
+}
+
+// markVIN 按配置为无效 VIN 的消息添加标记，quarantine 模式下按消息队列类型改投隔离 Topic 或路由键
+func (h *Handler) markVIN(p *usecase.MQPayload) {
+	vc := h.cfg.VINValidation
+	if !vc.Enabled || vc.Action == VINActionReject {
+		return
+	}
+	err := gbt32960.ValidateVIN(p.VIN)
+	if err == nil {
+		return
+	}
+	p.VINError = err.Error()
+	if vc.Action != VINActionQuarantine {
+		return
+	}
+	switch h.mqType {
+	case mqTypeKafka:
+		p.Topic = vc.QuarantineTopic
+	case mqTypeRabbitMQ:
+		p.Key = vc.QuarantineRoutingKey
+	}
+}
+
+// InvalidVINCounts 返回各来源 IP 的无效 VIN 报文计数
+func (h *Handler) InvalidVINCounts() map[string]uint64 {
+	out := make(map[string]uint64)
+	h.invalidVINs.Range(func(key, value interface{}) bool {
+		out[key.(string)] = atomic.LoadUint64(value.(*uint64))
+		return true
+	})
+	return out
+}
@@ -0,0 +1,47 @@
+package gbt32960
+
+import (
+	"testing"
+
+	"vehicle-gateway/internal/config"
+	"vehicle-gateway/internal/usecase"
+)
+
+func TestQuarantineTargetFollowsMQType(t *testing.T) {
+	topicOnly := config.VINValidationConfig{Enabled: true, Action: VINActionQuarantine, QuarantineTopic: "vehicle_data_quarantine"}
+	keyOnly := config.VINValidationConfig{Enabled: true, Action: VINActionQuarantine, QuarantineRoutingKey: "k_car.quarantine"}
+
+	tests := []struct {
+		name    string
+		vc      config.VINValidationConfig
+		mqType  string
+		wantErr bool
+		topic   string
+		key     string
+	}{
+		{"kafka 仅配置 topic", topicOnly, mqTypeKafka, false, "vehicle_data_quarantine", ""},
+		{"kafka 缺少 topic", keyOnly, mqTypeKafka, true, "", ""},
+		{"rabbitmq 仅配置路由键", keyOnly, mqTypeRabbitMQ, false, "", "k_car.quarantine"},
+		{"rabbitmq 缺少路由键", topicOnly, mqTypeRabbitMQ, true, "", ""},
+		{"MQ 关闭", topicOnly, "", false, "", ""},
+		{"无效 action", config.VINValidationConfig{Enabled: true, Action: "drop"}, mqTypeKafka, true, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateVINConfig(tt.vc, tt.mqType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateVINConfig() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			h := &Handler{cfg: config.GBT32960Config{VINValidation: tt.vc}, mqType: tt.mqType}
+			p := usecase.MQPayload{VIN: "INVALIDVIN"}
+			h.markVIN(&p)
+			if p.VINError == "" || p.Topic != tt.topic || p.Key != tt.key {
+				t.Errorf("VINError = %q, Topic = %q, Key = %q, 期望 Topic %q, Key %q", p.VINError, p.Topic, p.Key, tt.topic, tt.key)
+			}
+		})
+	}
+}
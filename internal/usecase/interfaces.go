@@ -7,6 +7,8 @@ type Conn interface {
 	RemoteAddr() string
 	Close() error
 	Write([]byte) (int, error)
+	// AsyncWrite 异步发送，事件循环之外 (如下行请求) 写连接时必须使用
+	AsyncWrite([]byte) error
 	SetPlatformAuthenticated(bool)
 	IsPlatformAuthenticated() bool
 }
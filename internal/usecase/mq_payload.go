@@ -1,12 +1,36 @@
 package usecase
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validityKey 模型中记录异常/无效字段的字段名 (gbt32960.FieldStatus)
+const validityKey = "Validity"
+
+// unitProvider 提供物理量单位的数据模型 (键为 JSON 字段路径)
+type unitProvider interface {
+	Units() map[string]string
+}
 
 // MQPayload 包装 RabbitMQ 消息，增加类型标识
 type MQPayload struct {
 	Type string      `json:"type"`
 	VIN  string      `json:"vin"`
 	Data interface{} `json:"data"`
+
+	Reissue     bool          `json:"reissue,omitempty"`    // 补发数据 (0x04)
+	CollectTime time.Time     `json:"collectTime"`          // 数据采集时间 (零值表示无)
+	ReceiveTime time.Time     `json:"receiveTime"`          // 网关接收时间
+	ClockDrift  time.Duration `json:"clockDrift,omitempty"` // 接收时间与采集时间之差 (仅有采集时间时)
+	TimeFlags   []string      `json:"timeFlags,omitempty"`  // 采集时间异常标记
+	VINError    string        `json:"vinError,omitempty"`   // VIN 校验失败原因
+	Partial     bool          `json:"partial,omitempty"`    // 宽松解码模式下的不完整数据
+
+	Topic string `json:"-"` // 目标 Topic, 为空时使用分发器默认值
+	Key   string `json:"-"` // 消息键 / 路由键, 为空时使用生产者默认值
 }
 
 func (p MQPayload) MarshalJSON() ([]byte, error) {
@@ -22,6 +46,29 @@ func (p MQPayload) MarshalJSON() ([]byte, error) {
 		// Injection possible
 		dataMap["msgType"] = p.Type
 		dataMap["vin"] = p.VIN
+		if p.Reissue {
+			dataMap["reissue"] = true
+		}
+		if !p.CollectTime.IsZero() {
+			dataMap["collectTime"] = p.CollectTime
+			dataMap["clockDriftMs"] = p.ClockDrift.Milliseconds()
+		}
+		if !p.ReceiveTime.IsZero() {
+			dataMap["receiveTime"] = p.ReceiveTime
+		}
+		if len(p.TimeFlags) > 0 {
+			dataMap["timeFlags"] = p.TimeFlags
+		}
+		if p.VINError != "" {
+			dataMap["vinError"] = p.VINError
+		}
+		if p.Partial {
+			dataMap["partial"] = true
+		}
+		if u, ok := p.Data.(unitProvider); ok {
+			dataMap["units"] = u.Units()
+		}
+		nullInvalidFields(dataMap)
 	} else {
 		// If Data is not a struct/map (e.g. primitive), we can't inject.
 		// However, for this project, Data is always a struct.
@@ -33,18 +80,81 @@ func (p MQPayload) MarshalJSON() ([]byte, error) {
 	// 3. Create a temporary struct to marshal the final JSON to avoid infinite recursion
 	type Alias MQPayload
 	// We use a map for the final data if injection succeeded
+	var collectTime, receiveTime *time.Time
+	var clockDriftMs *int64
+	if !p.CollectTime.IsZero() {
+		collectTime = &p.CollectTime
+		ms := p.ClockDrift.Milliseconds()
+		clockDriftMs = &ms
+	}
+	if !p.ReceiveTime.IsZero() {
+		receiveTime = &p.ReceiveTime
+	}
 	if dataMap != nil {
 		return json.Marshal(&struct {
-			Type string                 `json:"type"`
-			VIN  string                 `json:"vin"`
-			Data map[string]interface{} `json:"data"`
+			Type         string                 `json:"type"`
+			VIN          string                 `json:"vin"`
+			Reissue      bool                   `json:"reissue,omitempty"`
+			CollectTime  *time.Time             `json:"collectTime,omitempty"`
+			ReceiveTime  *time.Time             `json:"receiveTime,omitempty"`
+			ClockDriftMs *int64                 `json:"clockDriftMs,omitempty"`
+			TimeFlags    []string               `json:"timeFlags,omitempty"`
+			VINError     string                 `json:"vinError,omitempty"`
+			Partial      bool                   `json:"partial,omitempty"`
+			Data         map[string]interface{} `json:"data"`
 		}{
-			Type: p.Type,
-			VIN:  p.VIN,
-			Data: dataMap,
+			Type:         p.Type,
+			VIN:          p.VIN,
+			Reissue:      p.Reissue,
+			CollectTime:  collectTime,
+			ReceiveTime:  receiveTime,
+			ClockDriftMs: clockDriftMs,
+			TimeFlags:    p.TimeFlags,
+			VINError:     p.VINError,
+			Partial:      p.Partial,
+			Data:         dataMap,
 		})
 	}
 
 	// Fallback to default
 	return json.Marshal(Alias(p))
 }
+
+// nullInvalidFields 将 Validity 中列出的异常/无效字段 (含数组元素) 置为 null，递归处理嵌套的对象与数组
+func nullInvalidFields(v interface{}) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		if status, ok := val[validityKey].(map[string]interface{}); ok {
+			for field := range status {
+				if _, exists := val[field]; exists {
+					val[field] = nil
+				} else if name, i, ok := splitIndexed(field); ok {
+					// 数组元素 (如 CellVoltages[3])
+					if arr, ok := val[name].([]interface{}); ok && i < len(arr) {
+						arr[i] = nil
+					}
+				}
+			}
+		}
+		for _, child := range val {
+			nullInvalidFields(child)
+		}
+	case []interface{}:
+		for _, child := range val {
+			nullInvalidFields(child)
+		}
+	}
+}
+
+// splitIndexed 拆分数组元素字段名 "Name[i]"
+func splitIndexed(field string) (string, int, bool) {
+	open := strings.IndexByte(field, '[')
+	if open <= 0 || !strings.HasSuffix(field, "]") {
+		return "", 0, false
+	}
+	i, err := strconv.Atoi(field[open+1 : len(field)-1])
+	if err != nil || i < 0 {
+		return "", 0, false
+	}
+	return field[:open], i, true
+}